@@ -0,0 +1,190 @@
+package testingt
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RequireWithin fatals unless got is within pct percent of want. Handy for
+// timing and size assertions where an exact match is unreasonable.
+func RequireWithin(tb testing.TB, want, got, pct float64) {
+	tb.Helper()
+
+	dev := math.Abs(got-want) / math.Abs(want) * 100
+	if want == 0 {
+		dev = 0
+		if got != 0 {
+			dev = math.Inf(1)
+		}
+	}
+	if dev > pct {
+		tb.Fatalf("got %v, want %v within %v%%: deviation is %.2f%%", got, want, pct, dev)
+	}
+}
+
+// AssertSkipped runs fn against a FakeTB and fails unless fn called Skip or
+// Skipf with a message containing wantReason. It lets you test your skip
+// logic without actually skipping anything.
+func AssertSkipped(tb testing.TB, fn func(tb testing.TB), wantReason string) {
+	tb.Helper()
+
+	fake := RunFakeTB(tb, fn)
+	if !fake.Skipped() {
+		tb.Errorf("expected skip with reason containing %q, but fn was not skipped", wantReason)
+		return
+	}
+
+	reasons := fake.SkipReasons()
+	for _, r := range reasons {
+		if strings.Contains(r, wantReason) {
+			return
+		}
+	}
+	tb.Errorf("skipped with reasons %q, want one containing %q", reasons, wantReason)
+}
+
+// RequireStableString calls String on s iters times and fatals if the output
+// ever differs from the first call. It guards against map iteration order
+// leaking into String output. Any fmt.Stringer works, *Store included.
+func RequireStableString(tb testing.TB, s fmt.Stringer, iters int) {
+	tb.Helper()
+
+	want := s.String()
+	for i := 1; i < iters; i++ {
+		if got := s.String(); got != want {
+			tb.Fatalf("String output changed on call %d:\n\tgot:  %s\n\twant: %s", i+1, got, want)
+		}
+	}
+}
+
+// RequireSpeedup fatals unless seq/par is at least minRatio, i.e. the
+// parallel run was at least minRatio times faster than the sequential one.
+func RequireSpeedup(tb testing.TB, seq, par time.Duration, minRatio float64) {
+	tb.Helper()
+
+	if par <= 0 {
+		tb.Fatalf("parallel duration must be positive, got %s", par)
+	}
+	if ratio := float64(seq) / float64(par); ratio < minRatio {
+		tb.Fatalf("speedup of %.2fx (seq=%s par=%s) is below the minimum of %.2fx", ratio, seq, par, minRatio)
+	}
+}
+
+// blockGracePeriod is how long RequireBlocks waits before deciding start is
+// in fact blocked.
+const blockGracePeriod = 50 * time.Millisecond
+
+// RequireBlocks runs start in a goroutine and fatals if it returns before
+// unblock is called. Once start has been blocked for a grace period, unblock
+// is called and start must then return within timeout. Useful for testing
+// backpressure, e.g. writers against a full store.
+func RequireBlocks(tb testing.TB, start func(), unblock func(), timeout time.Duration) {
+	tb.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		start()
+	}()
+
+	select {
+	case <-done:
+		tb.Fatalf("start returned before unblock was called")
+	case <-time.After(blockGracePeriod):
+	}
+
+	unblock()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		tb.Fatalf("start did not return within %s of unblock", timeout)
+	}
+}
+
+// RequireSameElements fatals unless want and got hold the same strings, with
+// the same multiplicity, regardless of order.
+func RequireSameElements(tb testing.TB, want, got []string) {
+	tb.Helper()
+
+	RequireSameElementsFunc(tb, want, got, func(s string) string { return s })
+}
+
+// RequireSameElementsFunc fatals unless want and got hold the same elements,
+// with the same multiplicity, regardless of order. Elements are compared by
+// the string key returns for them.
+func RequireSameElementsFunc[T any](tb testing.TB, want, got []T, key func(T) string) {
+	tb.Helper()
+
+	counts := make(map[string]int)
+	for _, v := range want {
+		counts[key(v)]++
+	}
+	for _, v := range got {
+		counts[key(v)]--
+	}
+
+	var missing, extra []string
+	for _, k := range slices.Sorted(maps.Keys(counts)) {
+		for n := counts[k]; n > 0; n-- {
+			missing = append(missing, k)
+		}
+		for n := counts[k]; n < 0; n++ {
+			extra = append(extra, k)
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		tb.Fatalf("elements differ:\n\tmissing: %q\n\textra:   %q", missing, extra)
+	}
+}
+
+// RequireContainsSubsequence fatals unless needle appears in haystack in
+// order, though not necessarily contiguously. Useful for asserting partial
+// orderings of events.
+func RequireContainsSubsequence(tb testing.TB, haystack, needle []string) {
+	tb.Helper()
+
+	var matched int
+	for _, h := range haystack {
+		if matched < len(needle) && h == needle[matched] {
+			matched++
+		}
+	}
+	if matched < len(needle) {
+		tb.Fatalf("subsequence %q not found in %q: matched up to %q", needle, haystack, needle[:matched])
+	}
+}
+
+// RequireStringMatches fatals unless s.String() matches the regular
+// expression pattern. Useful when the format matters but the exact keys
+// don't. Any fmt.Stringer works, *Store included.
+func RequireStringMatches(tb testing.TB, s fmt.Stringer, pattern string) {
+	tb.Helper()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		tb.Fatalf("invalid pattern %q: %s", pattern, err)
+	}
+	if got := s.String(); !re.MatchString(got) {
+		tb.Fatalf("String output does not match %q:\n\tgot: %s", pattern, got)
+	}
+}
+
+// FailFast runs each step in order and fatals at the first one to return an
+// error, naming it by its position, 1 being the first. The remaining steps
+// are not run.
+func FailFast(tb testing.TB, steps ...func() error) {
+	tb.Helper()
+
+	for i, step := range steps {
+		if err := step(); err != nil {
+			tb.Fatalf("step %d of %d failed: %s", i+1, len(steps), err)
+		}
+	}
+}
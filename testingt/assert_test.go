@@ -0,0 +1,279 @@
+package testingt_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireWithin(t *testing.T) {
+	t.Run("in tolerance", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireWithin(tb, 100, 95, 5)
+			testingt.RequireWithin(tb, 100, 105, 5)
+			testingt.RequireWithin(tb, 0, 0, 0)
+		})
+
+		assert.False(t, fake.Failed())
+	})
+
+	t.Run("out of tolerance", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireWithin(tb, 100, 90, 5)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"got 90, want 100 within 5%: deviation is 10.00%"}, fake.Errors())
+	})
+}
+
+func TestAssertSkipped(t *testing.T) {
+	skipOnCI := func(tb testing.TB) {
+		tb.Skipf("skipping on CI: %s", "flaky network")
+	}
+
+	t.Run("skipped with reason", func(t *testing.T) {
+		testingt.AssertSkipped(t, skipOnCI, "flaky network")
+	})
+
+	t.Run("skipped with wrong reason", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.AssertSkipped(tb, skipOnCI, "no docker")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`skipped with reasons ["skipping on CI: flaky network"], want one containing "no docker"`}, fake.Errors())
+	})
+
+	t.Run("not skipped", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.AssertSkipped(tb, func(tb testing.TB) {}, "flaky network")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`expected skip with reason containing "flaky network", but fn was not skipped`}, fake.Errors())
+	})
+}
+
+func TestRequireStableString(t *testing.T) {
+	t.Run("sorted store is stable", func(t *testing.T) {
+		var s testingt.Store
+		for _, k := range []string{"e", "d", "c", "b", "a"} {
+			s.Add(k)
+		}
+
+		testingt.RequireStableString(t, &s, 100)
+	})
+
+	t.Run("unsorted stringer fails", func(t *testing.T) {
+		s := &rotatingStringer{keys: []string{"a", "b", "c"}}
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStableString(tb, s, 100)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"String output changed on call 2:\n\tgot:  [b c a]\n\twant: [a b c]"}, fake.Errors())
+	})
+}
+
+// rotatingStringer stands in for a String implementation that leaks map
+// iteration order, deterministically returning a different order per call.
+type rotatingStringer struct {
+	keys  []string
+	calls int
+}
+
+func (r *rotatingStringer) String() string {
+	i := r.calls % len(r.keys)
+	r.calls++
+	return fmt.Sprint(slices.Concat(r.keys[i:], r.keys[:i]))
+}
+
+func TestRequireSpeedup(t *testing.T) {
+	t.Run("meets minimum ratio", func(t *testing.T) {
+		testingt.RequireSpeedup(t, 4*time.Second, time.Second, 4)
+		testingt.RequireSpeedup(t, 9*time.Second, 1500*time.Millisecond, 2)
+	})
+
+	t.Run("below minimum ratio", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSpeedup(tb, 3*time.Second, 2*time.Second, 2)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"speedup of 1.50x (seq=3s par=2s) is below the minimum of 2.00x"}, fake.Errors())
+	})
+
+	t.Run("zero parallel duration", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSpeedup(tb, time.Second, 0, 2)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"parallel duration must be positive, got 0s"}, fake.Errors())
+	})
+}
+
+func TestRequireBlocks(t *testing.T) {
+	t.Run("channel gated fn", func(t *testing.T) {
+		gate := make(chan struct{})
+		testingt.RequireBlocks(t,
+			func() { <-gate },
+			func() { close(gate) },
+			time.Second,
+		)
+	})
+
+	t.Run("fn that never blocks", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireBlocks(tb, func() {}, func() {}, time.Second)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"start returned before unblock was called"}, fake.Errors())
+	})
+
+	t.Run("unblock doesn't release fn", func(t *testing.T) {
+		gate := make(chan struct{})
+		t.Cleanup(func() { close(gate) })
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireBlocks(tb, func() { <-gate }, func() {}, 10*time.Millisecond)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"start did not return within 10ms of unblock"}, fake.Errors())
+	})
+}
+
+func TestRequireSameElements(t *testing.T) {
+	t.Run("same elements in any order", func(t *testing.T) {
+		testingt.RequireSameElements(t, []string{"a", "b", "b", "c"}, []string{"b", "c", "b", "a"})
+	})
+
+	t.Run("differing multiplicity", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSameElements(tb, []string{"a", "b", "b"}, []string{"a", "b", "c"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"elements differ:\n\tmissing: [\"b\"]\n\textra:   [\"c\"]"}, fake.Errors())
+	})
+}
+
+func TestRequireSameElementsFunc(t *testing.T) {
+	type record struct {
+		ID   int
+		Name string
+	}
+	key := func(r record) string { return fmt.Sprintf("%d:%s", r.ID, r.Name) }
+
+	t.Run("structs in different orders", func(t *testing.T) {
+		want := []record{{1, "first"}, {2, "second"}, {3, "third"}}
+		got := []record{{3, "third"}, {1, "first"}, {2, "second"}}
+
+		testingt.RequireSameElementsFunc(t, want, got, key)
+	})
+
+	t.Run("differing structs", func(t *testing.T) {
+		want := []record{{1, "first"}, {2, "second"}}
+		got := []record{{2, "second"}, {1, "uno"}}
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSameElementsFunc(tb, want, got, key)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"elements differ:\n\tmissing: [\"1:first\"]\n\textra:   [\"1:uno\"]"}, fake.Errors())
+	})
+}
+
+func TestRequireContainsSubsequence(t *testing.T) {
+	haystack := []string{"start", "add a", "add b", "rm a", "add c", "stop"}
+
+	t.Run("present", func(t *testing.T) {
+		testingt.RequireContainsSubsequence(t, haystack, []string{"start", "rm a", "stop"})
+		testingt.RequireContainsSubsequence(t, haystack, []string{"add a", "add b"})
+		testingt.RequireContainsSubsequence(t, haystack, nil)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireContainsSubsequence(tb, haystack, []string{"start", "rm b"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], `matched up to ["start"]`)
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireContainsSubsequence(tb, haystack, []string{"rm a", "add a"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], `matched up to ["rm a"]`)
+	})
+}
+
+func TestRequireStringMatches(t *testing.T) {
+	var s testingt.Store
+	for _, k := range []string{"key-3", "key-1", "key-2"} {
+		require.NoError(t, s.Add(k))
+	}
+
+	t.Run("matching pattern", func(t *testing.T) {
+		testingt.RequireStringMatches(t, &s, `^\[(key-\d+ ?)+\]$`)
+	})
+
+	t.Run("non matching pattern", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStringMatches(tb, &s, `^\{.*\}$`)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"String output does not match \"^\\\\{.*\\\\}$\":\n\tgot: [key-1 key-2 key-3]"}, fake.Errors())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStringMatches(tb, &s, `[`)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], `invalid pattern "["`)
+	})
+}
+
+func TestFailFast(t *testing.T) {
+	t.Run("all steps pass", func(t *testing.T) {
+		var ran int
+		step := func() error { ran++; return nil }
+
+		testingt.FailFast(t, step, step, step)
+
+		assert.Equal(t, 3, ran)
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		var ran []int
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.FailFast(tb,
+				func() error { ran = append(ran, 1); return nil },
+				func() error { ran = append(ran, 2); return errors.New("boom") },
+				func() error { ran = append(ran, 3); return nil },
+			)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"step 2 of 3 failed: boom"}, fake.Errors())
+		assert.Equal(t, []int{1, 2}, ran, "step 3 should never run")
+	})
+}
@@ -0,0 +1,154 @@
+package testingt
+
+import (
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// BenchmarkStores benchmarks Add, Has, and Rm against the KeyStore produced
+// by factory. Wire it up once per implementation to compare their overhead:
+//
+//	func BenchmarkSyncStore(b *testing.B) {
+//		testingt.BenchmarkStores(b, func() testingt.KeyStore { return new(testingt.SyncStore) })
+//	}
+func BenchmarkStores(b *testing.B, factory func() KeyStore) {
+	b.Helper()
+
+	const numKeys = 1024
+	keys := make([]string, numKeys)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	b.Run("Add", func(b *testing.B) {
+		b.ReportAllocs()
+		s := factory()
+		for i := 0; i < b.N; i++ {
+			s.Add(keys[i%numKeys])
+		}
+	})
+
+	b.Run("Has", func(b *testing.B) {
+		b.ReportAllocs()
+		s := factory()
+		for _, k := range keys[:numKeys/2] {
+			s.Add(k)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			s.Has(keys[i%numKeys])
+		}
+	})
+
+	b.Run("Rm", func(b *testing.B) {
+		b.ReportAllocs()
+		s := factory()
+		for i := 0; i < b.N; i++ {
+			// refill the store off the clock each time the keys run out,
+			// so only removes of present keys are timed
+			if i%numKeys == 0 {
+				b.StopTimer()
+				for _, k := range keys {
+					s.Add(k)
+				}
+				b.StartTimer()
+			}
+			s.Rm(keys[i%numKeys])
+		}
+	})
+}
+
+// MeasureContention hammers s from the given number of goroutines for d and
+// returns the total number of operations completed. Each goroutine cycles
+// through Add, Has, and Rm on its own keys, so any drop in throughput comes
+// from lock contention rather than from the keys themselves.
+//
+// The numbers are only comparable on the same machine, use it to spot
+// locking regressions rather than asserting absolute performance.
+func MeasureContention(s *SyncStore, goroutines int, d time.Duration) (ops int64) {
+	var (
+		total atomic.Int64
+		stop  atomic.Bool
+		wg    sync.WaitGroup
+	)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k := "contention-" + strconv.Itoa(g)
+			var n int64
+			for !stop.Load() {
+				switch n % 3 {
+				case 0:
+					s.Add(k)
+				case 1:
+					s.Has(k)
+				case 2:
+					s.Rm(k)
+				}
+				n++
+			}
+			total.Add(n)
+		}()
+	}
+
+	time.Sleep(d)
+	stop.Store(true)
+	wg.Wait()
+
+	return total.Load()
+}
+
+// Latencies runs fn n times and returns how long each run took, in run
+// order. Pair it with Percentile to make assertions on the distribution.
+func Latencies(fn func(), n int) []time.Duration {
+	ds := make([]time.Duration, n)
+	for i := range ds {
+		start := time.Now()
+		fn()
+		ds[i] = time.Since(start)
+	}
+	return ds
+}
+
+// Percentile returns the pth percentile, 0 <= p <= 100, of ds using the
+// nearest rank method, a p of 0 returns the minimum. ds is left untouched.
+// The percentile of no durations is 0.
+func Percentile(ds []time.Duration, p float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+
+	sorted := slices.Sorted(slices.Values(ds))
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// BenchAllocBudget runs fn b.N times with allocations reported, and fails
+// the benchmark if fn allocated more than maxBytesPerOp bytes per op on
+// average. Any setup should happen before calling it, everything fn
+// allocates counts against the budget.
+func BenchAllocBudget(b *testing.B, maxBytesPerOp int64, fn func()) {
+	b.Helper()
+	b.ReportAllocs()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn()
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+
+	if perOp := int64(after.TotalAlloc-before.TotalAlloc) / int64(b.N); perOp > maxBytesPerOp {
+		b.Errorf("allocated %d B/op, over the budget of %d B/op", perOp, maxBytesPerOp)
+	}
+}
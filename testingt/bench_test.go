@@ -0,0 +1,112 @@
+package testingt_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func BenchmarkStore(b *testing.B) {
+	testingt.BenchmarkStores(b, func() testingt.KeyStore { return new(testingt.Store) })
+}
+
+func BenchmarkSyncStore(b *testing.B) {
+	testingt.BenchmarkStores(b, func() testingt.KeyStore { return new(testingt.SyncStore) })
+}
+
+func BenchmarkOrderedStore(b *testing.B) {
+	testingt.BenchmarkStores(b, func() testingt.KeyStore { return new(testingt.OrderedStore) })
+}
+
+func BenchmarkStore_AddAllocBudget(b *testing.B) {
+	b.Run("new keys", func(b *testing.B) {
+		// a key per op, generated up front so only the adds count
+		keys := make([]string, b.N)
+		for i := range keys {
+			keys[i] = "key-" + strconv.Itoa(i)
+		}
+
+		var (
+			s testingt.Store
+			i int
+		)
+		// map growth is amortized across the adds
+		testingt.BenchAllocBudget(b, 256, func() {
+			_ = s.Add(keys[i])
+			i++
+		})
+	})
+
+	b.Run("existing key", func(b *testing.B) {
+		var s testingt.Store
+		_ = s.Add("key-0")
+		// should be alloc free, the slack absorbs process wide noise
+		// when b.N is small
+		testingt.BenchAllocBudget(b, 32, func() {
+			_ = s.Add("key-0")
+		})
+	})
+}
+
+func TestMeasureContention(t *testing.T) {
+	// the minimum here is deliberately conservative, throughput depends on the
+	// machine (and the race detector) so we only guard against a store that's
+	// effectively deadlocked.
+	const minOps = 1000
+
+	var s testingt.SyncStore
+	ops := testingt.MeasureContention(&s, 8, 100*time.Millisecond)
+	t.Logf("completed %d ops", ops)
+
+	assert.GreaterOrEqual(t, ops, int64(minOps))
+}
+
+func TestLatencies(t *testing.T) {
+	var (
+		s     testingt.Store
+		calls int
+	)
+	ds := testingt.Latencies(func() {
+		calls++
+		// every 10th op is made artificially slow to give the tail some weight
+		if calls%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		_ = s.Add(strconv.Itoa(calls))
+	}, 100)
+
+	require.Len(t, ds, 100)
+	p50, p99 := testingt.Percentile(ds, 50), testingt.Percentile(ds, 99)
+	t.Logf("p50=%s p99=%s", p50, p99)
+	assert.LessOrEqual(t, p50, p99)
+	assert.GreaterOrEqual(t, p99, time.Millisecond)
+}
+
+func TestPercentile(t *testing.T) {
+	ds := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 0, want: 1},
+		{p: 10, want: 1},
+		{p: 50, want: 5},
+		{p: 90, want: 9},
+		{p: 99, want: 10},
+		{p: 100, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatFloat(tt.p, 'f', -1, 64), func(t *testing.T) {
+			assert.Equal(t, tt.want, testingt.Percentile(ds, tt.p))
+		})
+	}
+
+	assert.Equal(t, time.Duration(5), ds[0], "input should be left unsorted")
+	assert.Zero(t, testingt.Percentile(nil, 50))
+}
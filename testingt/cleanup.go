@@ -0,0 +1,123 @@
+package testingt
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// SafeCleanup registers fn as a cleanup that can't take the test binary
+// down with it. The testing package still runs the remaining cleanups when
+// one panics, but the panic itself is reported after the fact and muddles
+// which test it came from. SafeCleanup recovers the panic and reports it,
+// stack and all, via Errorf on the test that registered it.
+func SafeCleanup(tb testing.TB, fn func()) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				tb.Errorf("cleanup panicked: %v\n%s", r, debug.Stack())
+			}
+		}()
+		fn()
+	})
+}
+
+// PanicCollector collects the panics of the cleanups it wraps and reports
+// all of them, in the order they happened, once the test's cleanups are done.
+type PanicCollector struct {
+	mu     sync.Mutex
+	panics []string
+}
+
+// NewPanicCollector creates a PanicCollector that reports to tb. Its report
+// is registered as a cleanup straight away, so create the collector before
+// registering the cleanups it wraps to have the report run after them.
+func NewPanicCollector(tb testing.TB) *PanicCollector {
+	tb.Helper()
+
+	p := new(PanicCollector)
+	tb.Cleanup(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if len(p.panics) == 0 {
+			return
+		}
+		tb.Errorf("%d cleanup(s) panicked:\n%s", len(p.panics), strings.Join(p.panics, "\n"))
+	})
+	return p
+}
+
+// Wrap returns fn wrapped to recover and collect any panic, suitable to be
+// handed to t.Cleanup.
+func (p *PanicCollector) Wrap(fn func()) func() {
+	return func() {
+		defer func() {
+			if r := recover(); r != nil {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				p.panics = append(p.panics, fmt.Sprintf("panic %d: %v", len(p.panics)+1, r))
+			}
+		}()
+		fn()
+	}
+}
+
+// Setup runs each setup fn in order and registers the teardown each one
+// returns as a cleanup. Cleanups run LIFO, so teardowns run in the reverse
+// order of their setups, the way nested fixtures would unwind. A setup may
+// return a nil teardown when there's nothing to undo.
+//
+// Unlike most helpers here, Setup takes a *testing.T so the fixtures people
+// already have, typically func(t *testing.T) func(), can be passed as is.
+func Setup(t *testing.T, fns ...func(t *testing.T) func()) {
+	t.Helper()
+
+	for _, fn := range fns {
+		if teardown := fn(t); teardown != nil {
+			t.Cleanup(teardown)
+		}
+	}
+}
+
+// CleanupWithTimeout registers fn as a cleanup that fails the test if it
+// takes longer than d, catching teardown that hangs, e.g. on a connection
+// that's never closed. fn runs in its own goroutine, one that blows past d
+// is left behind rather than holding up the rest of the cleanups.
+func CleanupWithTimeout(tb testing.TB, d time.Duration, fn func()) {
+	tb.Helper()
+
+	tb.Cleanup(func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			fn()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(d):
+			tb.Errorf("cleanup did not complete within %s", d)
+		}
+	})
+}
+
+// WarnIfSlow registers a cleanup that logs a warning if the test took longer
+// than threshold, timed from the call to WarnIfSlow to the cleanup. It never
+// fails the test, a nudge for tests creeping up on a time budget rather than
+// a hard limit. The warning is logged even when the package is quiet, see
+// SetVerbose.
+func WarnIfSlow(tb testing.TB, threshold time.Duration) {
+	tb.Helper()
+
+	start := time.Now()
+	tb.Cleanup(func() {
+		if took := time.Since(start); took > threshold {
+			tb.Logf("WARNING: slow test: %s took %s, over the %s threshold", tb.Name(), took.Round(time.Millisecond), threshold)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package testingt_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestSafeCleanup(t *testing.T) {
+	t.Run("panic in cleanup is recorded as an error", func(t *testing.T) {
+		var ranAfter bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			// cleanups run LIFO, so this one runs after the panicking one
+			tb.Cleanup(func() { ranAfter = true })
+			testingt.SafeCleanup(tb, func() { panic("boom") })
+		})
+
+		require.True(t, fake.Failed())
+		require.Len(t, fake.Errors(), 1)
+		assert.Contains(t, fake.Errors()[0], "cleanup panicked: boom")
+		assert.Contains(t, fake.Errors()[0], "goroutine")
+		assert.True(t, ranAfter, "remaining cleanups should still run")
+	})
+
+	t.Run("well behaved cleanup is untouched", func(t *testing.T) {
+		var ran bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.SafeCleanup(tb, func() { ran = true })
+		})
+
+		assert.False(t, fake.Failed())
+		assert.True(t, ran)
+	})
+}
+
+func TestPanicCollector(t *testing.T) {
+	t.Run("reports every panic in order", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			pc := testingt.NewPanicCollector(tb)
+			tb.Cleanup(pc.Wrap(func() { panic("registered first, runs last") }))
+			tb.Cleanup(pc.Wrap(func() {}))
+			tb.Cleanup(pc.Wrap(func() { panic("registered last, runs first") }))
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			"2 cleanup(s) panicked:\n" +
+				"panic 1: registered last, runs first\n" +
+				"panic 2: registered first, runs last",
+		}, fake.Errors())
+	})
+
+	t.Run("quiet without panics", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			pc := testingt.NewPanicCollector(tb)
+			tb.Cleanup(pc.Wrap(func() {}))
+		})
+
+		assert.False(t, fake.Failed())
+	})
+}
+
+func TestSetup(t *testing.T) {
+	var order []string
+	fixture := func(name string) func(t *testing.T) func() {
+		return func(t *testing.T) func() {
+			order = append(order, "setup "+name)
+			return func() { order = append(order, "teardown "+name) }
+		}
+	}
+
+	t.Run("fixtures", func(t *testing.T) {
+		testingt.Setup(t,
+			fixture("db"),
+			func(t *testing.T) func() { return nil },
+			fixture("cache"),
+			fixture("server"),
+		)
+	})
+
+	assert.Equal(t, []string{
+		"setup db",
+		"setup cache",
+		"setup server",
+		"teardown server",
+		"teardown cache",
+		"teardown db",
+	}, order)
+}
+
+func TestCleanupWithTimeout(t *testing.T) {
+	t.Run("slow cleanup is reported", func(t *testing.T) {
+		hang := make(chan struct{})
+		t.Cleanup(func() { close(hang) })
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.CleanupWithTimeout(tb, 10*time.Millisecond, func() { <-hang })
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"cleanup did not complete within 10ms"}, fake.Errors())
+	})
+
+	t.Run("fast cleanup", func(t *testing.T) {
+		var ran atomic.Bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.CleanupWithTimeout(tb, time.Second, func() { ran.Store(true) })
+		})
+
+		assert.False(t, fake.Failed())
+		assert.True(t, ran.Load())
+	})
+}
+
+func TestWarnIfSlow(t *testing.T) {
+	t.Run("slow body is flagged", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.WarnIfSlow(tb, time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+		})
+
+		assert.False(t, fake.Failed(), "a slow test should not fail")
+		require.Len(t, fake.Logs(), 1)
+		assert.Contains(t, fake.Logs()[0], "WARNING: slow test: "+t.Name())
+		assert.Contains(t, fake.Logs()[0], "over the 1ms threshold")
+	})
+
+	t.Run("fast body is quiet", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.WarnIfSlow(tb, time.Minute)
+		})
+
+		assert.False(t, fake.Failed())
+		assert.Empty(t, fake.Logs())
+	})
+}
@@ -0,0 +1,170 @@
+package testingt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// CLIExitTimeout is the exit code of a keystore command whose context's
+// deadline passed before it completed, the same code timeout(1) uses.
+const CLIExitTimeout = 124
+
+const cliUsage = `usage: keystore [-file path] <command> [keys...]
+
+commands:
+  add [keys...]  add the keys, read one per line from stdin when none are given
+  rm keys...     remove the keys
+  list           list the keys, one per line
+
+flags:
+`
+
+// Config is the keystore command's configuration, parsed from its command
+// line by ParseArgs.
+type Config struct {
+	// File is the file the keys are persisted to, empty for in memory only.
+	File string
+	// Command is the subcommand to run, one of add, rm, or list.
+	Command string
+	// Keys are the arguments following the subcommand.
+	Keys []string
+}
+
+// cliCommands are the keystore command's subcommands.
+var cliCommands = []string{"add", "rm", "list"}
+
+func newCLIFlagSet(cfg *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet("keystore", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&cfg.File, "file", "", "file the keys are persisted to, in memory only when empty")
+	return fs
+}
+
+// ParseArgs parses the keystore command line args, without the program
+// name, into a Config. -h and -help return flag.ErrHelp.
+func ParseArgs(args []string) (Config, error) {
+	var cfg Config
+	fs := newCLIFlagSet(&cfg)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if fs.NArg() == 0 {
+		return Config{}, errors.New("no command given")
+	}
+	cfg.Command = fs.Arg(0)
+	if keys := fs.Args()[1:]; len(keys) > 0 {
+		cfg.Keys = keys
+	}
+	if !slices.Contains(cliCommands, cfg.Command) {
+		return Config{}, fmt.Errorf("unknown command %q", cfg.Command)
+	}
+	return cfg, nil
+}
+
+// printCLIUsage writes the keystore command's usage to w.
+func printCLIUsage(w io.Writer) {
+	fmt.Fprint(w, cliUsage)
+	fs := newCLIFlagSet(new(Config))
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+// CLIMain is the main logic of the keystore command, see cmd/keystore. It
+// runs the command for args, the command line without the program name,
+// reading from stdin and writing to stdout and stderr, and returns the exit
+// code: 0 on success, 1 when the command fails, 2 for a usage error, and
+// CLIExitTimeout when ctx's deadline cuts the command short. Taking its
+// streams and returning rather than exiting keeps it testable in-process,
+// see RunCLI.
+func CLIMain(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := ParseArgs(args)
+	if errors.Is(err, flag.ErrHelp) {
+		printCLIUsage(stderr)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "keystore: %s\n", err)
+		printCLIUsage(stderr)
+		return 2
+	}
+
+	var (
+		s     KeyStore = new(Store)
+		flush          = func() error { return nil }
+	)
+	if cfg.File != "" {
+		f, err := OpenFileStore(cfg.File)
+		if err != nil {
+			fmt.Fprintf(stderr, "keystore: %s\n", err)
+			return 1
+		}
+		s, flush = f, f.Flush
+	}
+
+	switch cfg.Command {
+	case "add":
+		if err = cliAdd(ctx, s, cfg.Keys, stdin, stdout); err == nil {
+			err = flush()
+		}
+	case "rm":
+		for _, k := range cfg.Keys {
+			s.Rm(k)
+		}
+		err = flush()
+	case "list":
+		for _, k := range s.Keys() {
+			fmt.Fprintln(stdout, k)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "keystore: %s\n", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return CLIExitTimeout
+		}
+		return 1
+	}
+	return 0
+}
+
+// cliAdd adds keys to s, or each line read from stdin when there are none.
+func cliAdd(ctx context.Context, s KeyStore, keys []string, stdin io.Reader, stdout io.Writer) error {
+	add := func(k string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.Add(k); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "added %s\n", k)
+		return nil
+	}
+
+	if len(keys) > 0 {
+		for _, k := range keys {
+			if err := add(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	sc := bufio.NewScanner(stdin)
+	for sc.Scan() {
+		if k := strings.TrimSpace(sc.Text()); k != "" {
+			if err := add(k); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read keys from stdin: %w", err)
+	}
+	return nil
+}
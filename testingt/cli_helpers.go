@@ -0,0 +1,118 @@
+package testingt
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CLIOpt configures a RunCLI invocation.
+type CLIOpt func(*cliOpts)
+
+type cliOpts struct {
+	timeout time.Duration
+}
+
+// WithCLITimeout cancels the command's context once d has passed. A command
+// respecting the deadline exits with CLIExitTimeout, one that's still
+// running a grace period after it fails the test.
+func WithCLITimeout(d time.Duration) CLIOpt {
+	return func(o *cliOpts) {
+		o.timeout = d
+	}
+}
+
+// RunCLI runs the keystore command's main logic, CLIMain, in-process with
+// args and stdin, returning what it wrote to stdout and stderr along with
+// its exit code. No binary is built or exec'd.
+func RunCLI(tb testing.TB, args []string, stdin string, opts ...CLIOpt) (stdout, stderr string, code int) {
+	tb.Helper()
+
+	var o cliOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx := context.Background()
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	var out, errOut strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		code = CLIMain(ctx, args, strings.NewReader(stdin), &out, &errOut)
+	}()
+
+	if o.timeout <= 0 {
+		<-done
+		return out.String(), errOut.String(), code
+	}
+	select {
+	case <-done:
+	case <-time.After(o.timeout + cancelTimeout):
+		tb.Fatalf("keystore %s did not return within %s of its %s timeout", strings.Join(args, " "), cancelTimeout, o.timeout)
+	}
+	return out.String(), errOut.String(), code
+}
+
+// GoldenCLI runs the keystore command with args, via RunCLI, and compares
+// its stdout to the golden file testdata/<name>.golden, fataling on any
+// difference or a nonzero exit code. Run the tests with -testingt.update to
+// write the output as the new golden file.
+func GoldenCLI(tb testing.TB, name string, args []string) {
+	tb.Helper()
+
+	stdout, stderr, code := RunCLI(tb, args, "")
+	if code != 0 {
+		tb.Fatalf("keystore %s exited with code %d:\n%s", strings.Join(args, " "), code, stderr)
+	}
+
+	path := goldenPath(name)
+	if want := string(readGolden(tb, path, []byte(stdout))); stdout != want {
+		tb.Fatalf("output of keystore %s does not match golden file %s, run with -testingt.update if this is expected:\n\tgot:\n%s\n\twant:\n%s", strings.Join(args, " "), path, stdout, want)
+	}
+}
+
+// RequireConfig parses args with ParseArgs and fatals unless it succeeds
+// with the want Config.
+func RequireConfig(tb testing.TB, args []string, want Config) {
+	tb.Helper()
+
+	got, err := ParseArgs(args)
+	if err != nil {
+		tb.Fatalf("failed to parse args %q: %s", args, err)
+	}
+	if got.File != want.File || got.Command != want.Command || !slices.Equal(got.Keys, want.Keys) {
+		tb.Fatalf("unexpected config for args %q:\n\tgot:  %+v\n\twant: %+v", args, got, want)
+	}
+}
+
+// cliPersistenceKey is the key RequireCLIPersistence round trips.
+const cliPersistenceKey = "testingt-cli-persistence"
+
+// RequireCLIPersistence runs a keystore add writing to a file in dir, then a
+// separate list reading it back, and fatals unless the added key survives
+// the trip through the file.
+func RequireCLIPersistence(tb testing.TB, dir string) {
+	tb.Helper()
+
+	file := filepath.Join(dir, "keys.json")
+	if _, stderr, code := RunCLI(tb, []string{"-file", file, "add", cliPersistenceKey}, ""); code != 0 {
+		tb.Fatalf("keystore add exited with code %d:\n%s", code, stderr)
+	}
+
+	stdout, stderr, code := RunCLI(tb, []string{"-file", file, "list"}, "")
+	if code != 0 {
+		tb.Fatalf("keystore list exited with code %d:\n%s", code, stderr)
+	}
+	if !slices.Contains(strings.Split(stdout, "\n"), cliPersistenceKey) {
+		tb.Fatalf("key %q added by one run was not listed by the next:\n%s", cliPersistenceKey, stdout)
+	}
+}
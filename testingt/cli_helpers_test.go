@@ -0,0 +1,187 @@
+package testingt_test
+
+import (
+	"flag"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRunCLI(t *testing.T) {
+	t.Run("add keys from args", func(t *testing.T) {
+		stdout, stderr, code := testingt.RunCLI(t, []string{"add", "first", "second"}, "")
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "added first\nadded second\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("add keys from stdin", func(t *testing.T) {
+		stdout, _, code := testingt.RunCLI(t, []string{"add"}, "first\n\n  second  \n")
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "added first\nadded second\n", stdout)
+	})
+
+	t.Run("add fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "keys.json")
+
+		stdout, stderr, code := testingt.RunCLI(t, []string{"-file", path, "add", "first"}, "")
+
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "added first\n", stdout)
+		assert.Contains(t, stderr, "keystore: failed to flush file store")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		stdout, stderr, code := testingt.RunCLI(t, []string{"nope"}, "")
+
+		assert.Equal(t, 2, code)
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, `keystore: unknown command "nope"`)
+		assert.Contains(t, stderr, "usage: keystore")
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		_, stderr, code := testingt.RunCLI(t, nil, "")
+
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "usage: keystore")
+	})
+}
+
+func TestGoldenCLI(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		testingt.GoldenCLI(t, "cli_list", []string{"-file", "testdata/cli_keys.json", "list"})
+	})
+
+	t.Run("list of an empty store", func(t *testing.T) {
+		setUpdate(t, false)
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.GoldenCLI(tb, "cli_list", []string{"list"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "output of keystore list does not match golden file testdata/cli_list.golden")
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		setUpdate(t, false)
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.GoldenCLI(tb, "cli_list", []string{"nope"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "keystore nope exited with code 2")
+	})
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Run("valid flags", func(t *testing.T) {
+		testingt.RequireConfig(t, []string{"-file", "keys.json", "add", "first", "second"}, testingt.Config{
+			File:    "keys.json",
+			Command: "add",
+			Keys:    []string{"first", "second"},
+		})
+	})
+
+	t.Run("default values", func(t *testing.T) {
+		testingt.RequireConfig(t, []string{"list"}, testingt.Config{Command: "list"})
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		_, err := testingt.ParseArgs([]string{"-verbose", "list"})
+
+		assert.ErrorContains(t, err, "flag provided but not defined: -verbose")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := testingt.ParseArgs([]string{"nope"})
+
+		assert.EqualError(t, err, `unknown command "nope"`)
+	})
+
+	t.Run("help", func(t *testing.T) {
+		_, err := testingt.ParseArgs([]string{"-h"})
+
+		assert.ErrorIs(t, err, flag.ErrHelp)
+	})
+}
+
+func TestRequireConfig(t *testing.T) {
+	t.Run("mismatch", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireConfig(tb, []string{"list"}, testingt.Config{File: "keys.json", Command: "list"})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			"unexpected config for args [\"list\"]:\n\tgot:  {File: Command:list Keys:[]}\n\twant: {File:keys.json Command:list Keys:[]}",
+		}, fake.Errors())
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireConfig(tb, nil, testingt.Config{})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"failed to parse args []: no command given"}, fake.Errors())
+	})
+}
+
+func TestRequireCLIPersistence(t *testing.T) {
+	t.Run("keys round trip", func(t *testing.T) {
+		dir := t.TempDir()
+
+		testingt.RequireCLIPersistence(t, dir)
+
+		stdout, _, code := testingt.RunCLI(t, []string{"-file", filepath.Join(dir, "keys.json"), "list"}, "")
+		assert.Equal(t, 0, code)
+		assert.NotEmpty(t, stdout)
+	})
+
+	t.Run("unwritable dir", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "missing")
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireCLIPersistence(tb, dir)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "keystore add exited with code 1")
+		assert.NoDirExists(t, dir)
+	})
+}
+
+func TestWithCLITimeout(t *testing.T) {
+	t.Run("slow add is cut short", func(t *testing.T) {
+		// adding this many keys from stdin takes far longer than the
+		// timeout, the command checks its context between keys
+		var keys strings.Builder
+		for i := range 200_000 {
+			keys.WriteString("key-" + strconv.Itoa(i) + "\n")
+		}
+		file := filepath.Join(t.TempDir(), "keys.json")
+
+		_, stderr, code := testingt.RunCLI(t, []string{"-file", file, "add"}, keys.String(), testingt.WithCLITimeout(time.Millisecond))
+
+		assert.Equal(t, testingt.CLIExitTimeout, code)
+		assert.Contains(t, stderr, "keystore: context deadline exceeded")
+		assert.NoFileExists(t, file, "a timed out add should not flush")
+	})
+
+	t.Run("fast command within the timeout", func(t *testing.T) {
+		stdout, _, code := testingt.RunCLI(t, []string{"add", "first"}, "", testingt.WithCLITimeout(time.Minute))
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "added first\n", stdout)
+	})
+}
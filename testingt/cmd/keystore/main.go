@@ -0,0 +1,18 @@
+// Command keystore manages a set of keys persisted to a file, a small CLI
+// over testingt's stores. See testingt.CLIMain for usage.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := testingt.CLIMain(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
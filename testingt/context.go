@@ -0,0 +1,81 @@
+package testingt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// cancelTimeout is how long a fn has to return after being handed a
+// cancelled context before it's considered to be ignoring cancellation.
+const cancelTimeout = 100 * time.Millisecond
+
+// RequireRespectsCancel calls fn with an already cancelled context and fatals
+// unless fn promptly returns an error matching context.Canceled.
+func RequireRespectsCancel(tb testing.TB, fn func(ctx context.Context) error) {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errc := make(chan error, 1)
+	go func() { errc <- fn(ctx) }()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			tb.Fatalf("got error %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(cancelTimeout):
+		tb.Fatalf("fn did not return within %s of cancellation", cancelTimeout)
+	}
+}
+
+// RequireDeadlinePropagated calls fn with a context carrying a known
+// deadline and fatals unless the deadline fn reports having observed is that
+// same deadline. Use it to check a call chain doesn't drop the caller's
+// context along the way.
+func RequireDeadlinePropagated(tb testing.TB, fn func(ctx context.Context) (time.Time, bool)) {
+	tb.Helper()
+
+	want := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+
+	got, ok := fn(ctx)
+	switch {
+	case !ok:
+		tb.Fatalf("fn observed no deadline, want %s", want)
+	case !got.Equal(want):
+		tb.Fatalf("fn observed deadline %s, want %s", got, want)
+	}
+}
+
+// workerGracePeriod is how long a BackgroundWorker has to return once its
+// context is cancelled.
+const workerGracePeriod = 100 * time.Millisecond
+
+// BackgroundWorker runs fn in a goroutine for the rest of the test. fn is
+// handed a context that's cancelled in cleanup, and the test fails if fn
+// doesn't return within a short grace period of that, catching goroutines
+// that outlive the test that started them.
+func BackgroundWorker(tb testing.TB, fn func(ctx context.Context)) {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(ctx)
+	}()
+
+	tb.Cleanup(func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(workerGracePeriod):
+			tb.Errorf("background worker did not stop within %s of cancellation", workerGracePeriod)
+		}
+	})
+}
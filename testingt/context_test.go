@@ -0,0 +1,131 @@
+package testingt_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireRespectsCancel(t *testing.T) {
+	t.Run("compliant fn", func(t *testing.T) {
+		testingt.RequireRespectsCancel(t, func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Minute):
+				return nil
+			}
+		})
+	})
+
+	t.Run("fn ignoring cancellation", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireRespectsCancel(tb, func(ctx context.Context) error {
+				return nil
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"got error <nil>, want context canceled"}, fake.Errors())
+	})
+
+	t.Run("fn hanging past cancellation", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireRespectsCancel(tb, func(ctx context.Context) error {
+				<-release
+				return ctx.Err()
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"fn did not return within 100ms of cancellation"}, fake.Errors())
+	})
+}
+
+func TestRequireDeadlinePropagated(t *testing.T) {
+	t.Run("propagating fn", func(t *testing.T) {
+		testingt.RequireDeadlinePropagated(t, func(ctx context.Context) (time.Time, bool) {
+			// a derived context keeps its parent's deadline
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			return ctx.Deadline()
+		})
+	})
+
+	t.Run("fn dropping the context", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireDeadlinePropagated(tb, func(ctx context.Context) (time.Time, bool) {
+				return context.Background().Deadline()
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "fn observed no deadline")
+	})
+
+	t.Run("fn swapping the deadline", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireDeadlinePropagated(tb, func(ctx context.Context) (time.Time, bool) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				return ctx.Deadline()
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "fn observed deadline")
+	})
+}
+
+func TestBackgroundWorker(t *testing.T) {
+	t.Run("worker shuts down cleanly", func(t *testing.T) {
+		var (
+			ticks   atomic.Int32
+			stopped atomic.Bool
+		)
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.BackgroundWorker(tb, func(ctx context.Context) {
+				defer stopped.Store(true)
+
+				ticker := time.NewTicker(time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						ticks.Add(1)
+					}
+				}
+			})
+
+			assert.Eventually(t, func() bool { return ticks.Load() > 0 }, time.Second, time.Millisecond)
+		})
+
+		assert.False(t, fake.Failed(), fake.Errors())
+		assert.True(t, stopped.Load(), "worker should have returned by the end of cleanup")
+	})
+
+	t.Run("worker ignoring cancellation", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.BackgroundWorker(tb, func(ctx context.Context) {
+				<-release
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"background worker did not stop within 100ms of cancellation"}, fake.Errors())
+	})
+}
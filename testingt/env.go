@@ -0,0 +1,64 @@
+package testingt
+
+import (
+	"maps"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// RunMatrix runs body once per entry in matrix, each as a subtest named by
+// the outer key. The inner map holds the env vars to set for that subtest,
+// they're restored once the subtest completes. Subtests run in sorted name
+// order so output is stable between runs.
+//
+// Like t.Setenv, RunMatrix can't be used with t.Parallel.
+func RunMatrix(t *testing.T, matrix map[string]map[string]string, body func(t *testing.T)) {
+	t.Helper()
+
+	for _, name := range slices.Sorted(maps.Keys(matrix)) {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range matrix[name] {
+				t.Setenv(k, v)
+			}
+			body(t)
+		})
+	}
+}
+
+// WithMaxProcs sets GOMAXPROCS to n for the rest of the test, restoring the
+// previous value in cleanup. Use it to reproduce single vs multi core
+// scheduling behavior. GOMAXPROCS is process wide, so like t.Setenv it
+// doesn't mix with t.Parallel.
+func WithMaxProcs(tb testing.TB, n int) {
+	tb.Helper()
+
+	prev := runtime.GOMAXPROCS(n)
+	tb.Cleanup(func() { runtime.GOMAXPROCS(prev) })
+}
+
+// stabilityLocales are the LC_ALL values RequireLocaleStable runs under, a
+// mix of byte wise and collation heavy locales.
+var stabilityLocales = []string{"C", "en_US.UTF-8", "sv_SE.UTF-8", "tr_TR.UTF-8"}
+
+// RequireLocaleStable calls fn under several LC_ALL settings and fails
+// unless every call returns the same output. Go's own sorting is byte wise
+// and ignores the locale, this guards against anything locale sensitive
+// (cgo, an exec'd sort, ...) sneaking into fn's output. LC_ALL is restored
+// once the test completes, so like t.Setenv it doesn't mix with t.Parallel.
+func RequireLocaleStable(tb testing.TB, fn func() string) {
+	tb.Helper()
+
+	var want string
+	for i, loc := range stabilityLocales {
+		tb.Setenv("LC_ALL", loc)
+		got := fn()
+		if i == 0 {
+			want = got
+			continue
+		}
+		if got != want {
+			tb.Fatalf("output under LC_ALL=%s differs from LC_ALL=%s:\n\tgot:  %s\n\twant: %s", loc, stabilityLocales[0], got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package testingt_test
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRunMatrix(t *testing.T) {
+	matrix := map[string]map[string]string{
+		"texas": {"THREEVE": "$TEXAS", "DEMO_REGION": "south"},
+		"maine": {"THREEVE": "$MAINE", "DEMO_REGION": "north"},
+	}
+
+	seen := make(map[string][2]string)
+	testingt.RunMatrix(t, matrix, func(t *testing.T) {
+		seen[path.Base(t.Name())] = [2]string{os.Getenv("THREEVE"), os.Getenv("DEMO_REGION")}
+	})
+
+	assert.Equal(t, map[string][2]string{
+		"texas": {"$TEXAS", "south"},
+		"maine": {"$MAINE", "north"},
+	}, seen)
+
+	_, ok := os.LookupEnv("DEMO_REGION")
+	assert.False(t, ok, "env should be restored after the matrix runs")
+}
+
+func TestWithMaxProcs(t *testing.T) {
+	before := runtime.GOMAXPROCS(0)
+
+	t.Run("single core", func(t *testing.T) {
+		testingt.WithMaxProcs(t, 1)
+
+		assert.Equal(t, 1, runtime.GOMAXPROCS(0))
+	})
+
+	assert.Equal(t, before, runtime.GOMAXPROCS(0), "GOMAXPROCS should be restored after the subtest")
+}
+
+func TestRequireLocaleStable(t *testing.T) {
+	t.Run("byte wise sorting", func(t *testing.T) {
+		var s testingt.Store
+		for _, k := range []string{"zebra", "Äpple", "apple", "ångström", "Zulu", "ı", "i"} {
+			require.NoError(t, s.Add(k))
+		}
+
+		testingt.RequireLocaleStable(t, s.String)
+	})
+
+	t.Run("locale dependent output", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireLocaleStable(tb, func() string {
+				return "sorted for " + os.Getenv("LC_ALL")
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "output under LC_ALL=en_US.UTF-8 differs from LC_ALL=C")
+	})
+}
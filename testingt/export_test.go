@@ -0,0 +1,5 @@
+package testingt
+
+// RequireImplementsOf exposes the check behind RequireImplements to the
+// external tests, a *Store can't be made to fail it.
+var RequireImplementsOf = requireImplements
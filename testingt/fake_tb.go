@@ -0,0 +1,256 @@
+package testingt
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FakeTB is a testing.TB that records failures, skips, and logs instead of
+// acting on them. It lets us assert how our own helpers behave when they
+// fail, without failing the test doing the asserting.
+//
+// Anything FakeTB doesn't override (TempDir, Name, Setenv, ...) is
+// delegated to the parent TB it was created with.
+type FakeTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	logs     []string
+	errs     []string
+	frames   []runtime.Frame
+	skips    []string
+	cleanups []func()
+	helpers  map[string]struct{}
+}
+
+// RunFakeTB runs fn against a fresh FakeTB and returns it for inspection.
+// fn runs in its own goroutine so that FailNow and SkipNow can halt it
+// without halting the caller. Cleanups registered by fn are run in LIFO
+// order once fn is done, just like the testing package does.
+func RunFakeTB(tb testing.TB, fn func(tb testing.TB)) *FakeTB {
+	tb.Helper()
+
+	f := &FakeTB{TB: tb}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer f.runCleanups()
+		fn(f)
+	}()
+	<-done
+	return f
+}
+
+func (f *FakeTB) runCleanups() {
+	for {
+		f.mu.Lock()
+		if len(f.cleanups) == 0 {
+			f.mu.Unlock()
+			return
+		}
+		fn := f.cleanups[len(f.cleanups)-1]
+		f.cleanups = f.cleanups[:len(f.cleanups)-1]
+		f.mu.Unlock()
+
+		fn()
+	}
+}
+
+// Helper marks the calling function as a test helper. Like the testing
+// package, errors are attributed to the first caller that isn't a helper,
+// see ErrorFrames.
+func (f *FakeTB) Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.helpers == nil {
+		f.helpers = make(map[string]struct{})
+	}
+	f.helpers[frame.Function] = struct{}{}
+}
+
+// fakeTBMethodPrefix identifies FakeTB's own methods in a stack trace.
+var fakeTBMethodPrefix = reflect.TypeFor[FakeTB]().PkgPath() + ".(*FakeTB)."
+
+// caller returns the frame an error should be attributed to: the first
+// frame outside of FakeTB that isn't a marked helper.
+func (f *FakeTB) caller() runtime.Frame {
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	frames := runtime.CallersFrames(pcs)
+	var last runtime.Frame
+	for {
+		frame, more := frames.Next()
+		last = frame
+		_, helper := f.helpers[frame.Function]
+		if !helper && !strings.HasPrefix(frame.Function, fakeTBMethodPrefix) {
+			return frame
+		}
+		if !more {
+			return last
+		}
+	}
+}
+
+// Cleanup registers fn to be called once the function given to RunFakeTB
+// completes.
+func (f *FakeTB) Cleanup(fn func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cleanups = append(f.cleanups, fn)
+}
+
+// Log records the args as a log line.
+func (f *FakeTB) Log(args ...any) {
+	f.log(fmt.Sprintln(args...))
+}
+
+// Logf records the formatted log line.
+func (f *FakeTB) Logf(format string, args ...any) {
+	f.log(fmt.Sprintf(format, args...))
+}
+
+func (f *FakeTB) log(s string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, trimNewline(s))
+}
+
+// Error records the args as an error and marks the FakeTB failed.
+func (f *FakeTB) Error(args ...any) {
+	f.error(fmt.Sprintln(args...))
+}
+
+// Errorf records the formatted error and marks the FakeTB failed.
+func (f *FakeTB) Errorf(format string, args ...any) {
+	f.error(fmt.Sprintf(format, args...))
+}
+
+func (f *FakeTB) error(s string) {
+	frame := f.caller()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs = append(f.errs, trimNewline(s))
+	f.frames = append(f.frames, frame)
+	f.failed = true
+}
+
+// Fatal is equivalent to Error followed by FailNow.
+func (f *FakeTB) Fatal(args ...any) {
+	f.Error(args...)
+	f.FailNow()
+}
+
+// Fatalf is equivalent to Errorf followed by FailNow.
+func (f *FakeTB) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+	f.FailNow()
+}
+
+// Fail marks the FakeTB failed.
+func (f *FakeTB) Fail() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = true
+}
+
+// FailNow marks the FakeTB failed and stops the calling goroutine.
+func (f *FakeTB) FailNow() {
+	f.Fail()
+	runtime.Goexit()
+}
+
+// Failed reports whether the FakeTB has been marked failed.
+func (f *FakeTB) Failed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failed
+}
+
+// Skip is equivalent to Log followed by SkipNow, the message is recorded
+// as a skip reason.
+func (f *FakeTB) Skip(args ...any) {
+	f.skip(fmt.Sprintln(args...))
+}
+
+// Skipf is equivalent to Logf followed by SkipNow, the message is recorded
+// as a skip reason.
+func (f *FakeTB) Skipf(format string, args ...any) {
+	f.skip(fmt.Sprintf(format, args...))
+}
+
+func (f *FakeTB) skip(s string) {
+	f.mu.Lock()
+	f.skips = append(f.skips, trimNewline(s))
+	f.mu.Unlock()
+	f.SkipNow()
+}
+
+// SkipNow marks the FakeTB skipped and stops the calling goroutine.
+func (f *FakeTB) SkipNow() {
+	f.mu.Lock()
+	f.skipped = true
+	f.mu.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped reports whether the FakeTB has been skipped.
+func (f *FakeTB) Skipped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.skipped
+}
+
+// Logs returns the recorded log lines.
+func (f *FakeTB) Logs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.logs)
+}
+
+// Errors returns the recorded error messages.
+func (f *FakeTB) Errors() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.errs)
+}
+
+// ErrorFrames returns the frame each recorded error is attributed to, in the
+// same order as Errors. That's the frame the testing package would report
+// the error's file and line from.
+func (f *FakeTB) ErrorFrames() []runtime.Frame {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.frames)
+}
+
+// SkipReasons returns the messages given to Skip and Skipf.
+func (f *FakeTB) SkipReasons() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.skips)
+}
+
+func trimNewline(s string) string {
+	if n := len(s); n > 0 && s[n-1] == '\n' {
+		return s[:n-1]
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package testingt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestFakeTB(t *testing.T) {
+	t.Run("Fatal halts the fn and records the error", func(t *testing.T) {
+		var reached bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			tb.Fatal("first")
+			reached = true
+		})
+
+		assert.True(t, fake.Failed())
+		assert.Equal(t, []string{"first"}, fake.Errors())
+		assert.False(t, reached)
+	})
+
+	t.Run("Skipf halts the fn and records the reason", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			tb.Skipf("not on %s", "tuesdays")
+		})
+
+		assert.True(t, fake.Skipped())
+		assert.False(t, fake.Failed())
+		assert.Equal(t, []string{"not on tuesdays"}, fake.SkipReasons())
+	})
+
+	t.Run("cleanups run LIFO after the fn is done", func(t *testing.T) {
+		var order []string
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			tb.Cleanup(func() { order = append(order, "first") })
+			tb.Cleanup(func() { order = append(order, "second") })
+			tb.Log("logged", 1)
+			tb.FailNow()
+		})
+
+		assert.Equal(t, []string{"second", "first"}, order)
+		assert.Equal(t, []string{"logged 1"}, fake.Logs())
+	})
+}
+
+func TestFakeTB_ErrorFrames(t *testing.T) {
+	t.Run("without Helper the helper is reported", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			fakeFailWithoutHelper(tb)
+		})
+
+		frames := fake.ErrorFrames()
+		require.Len(t, frames, 1)
+		assert.True(t, strings.HasSuffix(frames[0].Function, ".fakeFailWithoutHelper"), frames[0].Function)
+	})
+
+	t.Run("with Helper the caller is reported", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			fakeFailWithHelper(tb)
+		})
+
+		frames := fake.ErrorFrames()
+		require.Len(t, frames, 1)
+		assert.Contains(t, frames[0].Function, "TestFakeTB_ErrorFrames")
+	})
+}
+
+func fakeFailWithoutHelper(tb testing.TB) {
+	tb.Error("failing in fakeFailWithoutHelper")
+}
+
+func fakeFailWithHelper(tb testing.TB) {
+	tb.Helper()
+	tb.Error("failing in fakeFailWithHelper")
+}
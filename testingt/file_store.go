@@ -0,0 +1,119 @@
+package testingt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileStore is a Store persisted to a file. Mutations only touch memory
+// until Flush writes them to disk.
+type FileStore struct {
+	path  string
+	store Store
+}
+
+var _ KeyStore = (*FileStore)(nil)
+
+// OpenFileStore opens the FileStore persisted at path, loading any keys
+// previously flushed to it. A missing file is an empty store.
+func OpenFileStore(path string) (*FileStore, error) {
+	f := &FileStore{path: path}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileStore) load() error {
+	f.store = Store{}
+
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file store: %w", err)
+	}
+
+	var keys []string
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return fmt.Errorf("failed to decode file store %s: %w", f.path, err)
+	}
+	for _, k := range keys {
+		if err := f.store.Add(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the store's keys to disk. The file is replaced atomically, a
+// crash mid flush leaves the previously flushed keys intact.
+func (f *FileStore) Flush() error {
+	b, err := json.Marshal(f.store.Keys())
+	if err != nil {
+		return fmt.Errorf("failed to encode file store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to flush file store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to flush file store: %w", err)
+	}
+	// without syncing, a crash after the rename can leave the new name
+	// pointing at data that never made it to disk
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to flush file store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to flush file store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to flush file store: %w", err)
+	}
+	return nil
+}
+
+// Path returns the path the store is persisted to.
+func (f *FileStore) Path() string {
+	return f.path
+}
+
+// Add adds the key to the store.
+func (f *FileStore) Add(k string) error {
+	return f.store.Add(k)
+}
+
+// Rm removes the key from the store.
+func (f *FileStore) Rm(k string) {
+	f.store.Rm(k)
+}
+
+// Has reports whether the key is in the store.
+func (f *FileStore) Has(k string) bool {
+	return f.store.Has(k)
+}
+
+// Len returns the number of keys in the store.
+func (f *FileStore) Len() int {
+	return f.store.Len()
+}
+
+// Keys returns the keys in sorted order.
+func (f *FileStore) Keys() []string {
+	return f.store.Keys()
+}
+
+func (f *FileStore) String() string {
+	return f.store.String()
+}
@@ -0,0 +1,14 @@
+package testingt
+
+import "testing"
+
+// SimulateCrash discards everything f holds in memory and reloads it from
+// disk, as if the process had crashed and restarted. Anything not flushed is
+// lost, making it easy to assert on durability. Fatals if the reload fails.
+func SimulateCrash(tb testing.TB, f *FileStore) {
+	tb.Helper()
+
+	if err := f.load(); err != nil {
+		tb.Fatalf("failed to reload after simulated crash: %s", err)
+	}
+}
@@ -0,0 +1,25 @@
+package testingt_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestSimulateCrash(t *testing.T) {
+	f, err := testingt.OpenFileStore(filepath.Join(t.TempDir(), "store.json"))
+	require.NoError(t, err)
+
+	require.NoError(t, f.Add("first"))
+	require.NoError(t, f.Add("second"))
+	require.NoError(t, f.Flush())
+	require.NoError(t, f.Add("unflushed"))
+
+	testingt.SimulateCrash(t, f)
+
+	assert.Equal(t, []string{"first", "second"}, f.Keys())
+}
@@ -0,0 +1,38 @@
+package testingt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("reopen sees flushed keys", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+
+		f, err := testingt.OpenFileStore(path)
+		require.NoError(t, err)
+		assert.Zero(t, f.Len(), "missing file should be an empty store")
+
+		require.NoError(t, f.Add("first"))
+		require.NoError(t, f.Add("second"))
+		require.NoError(t, f.Flush())
+
+		reopened, err := testingt.OpenFileStore(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, reopened.Keys())
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o644))
+
+		_, err := testingt.OpenFileStore(path)
+		require.ErrorContains(t, err, "failed to decode file store")
+	})
+}
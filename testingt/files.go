@@ -0,0 +1,140 @@
+package testingt
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CopyDir recursively copies src into a fresh t.TempDir and returns the new
+// root, preserving file modes. Handy when a test needs a writable copy of a
+// read-only fixture directory. Directories always keep owner rwx so the copy
+// stays writable and the TempDir can be cleaned up.
+func CopyDir(tb testing.TB, src string) string {
+	tb.Helper()
+
+	dst := tb.TempDir()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm()|0o700)
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+	if err != nil {
+		tb.Fatalf("failed to copy dir %s: %s", src, err)
+	}
+
+	return dst
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// OpenFile's perm is subject to the umask, so set the mode explicitly
+	return os.Chmod(dst, perm)
+}
+
+// LoadKeysFile adds each line of the file at path to s as a key. Blank lines
+// and lines starting with # are skipped, surrounding whitespace is trimmed.
+// Fatals on any I/O or store error.
+func LoadKeysFile(tb testing.TB, s *Store, path string) {
+	tb.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Fatalf("failed to open keys file: %s", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := s.Add(line); err != nil {
+			tb.Fatalf("failed to add key %q from %s: %s", line, path, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		tb.Fatalf("failed to read keys file %s: %s", path, err)
+	}
+}
+
+// TempFileTracker creates temp files via os.CreateTemp and removes them once
+// the test completes. See TrackTempFiles.
+type TempFileTracker struct {
+	tb    testing.TB
+	files []*os.File
+}
+
+// TrackTempFiles returns a TempFileTracker for tests that need os.CreateTemp
+// directly rather than t.TempDir. Every file created through it is closed and
+// removed in cleanup, and the test fails if any of them are left behind.
+func TrackTempFiles(tb testing.TB) *TempFileTracker {
+	tb.Helper()
+
+	tr := &TempFileTracker{tb: tb}
+	tb.Cleanup(func() {
+		for _, f := range tr.files {
+			f.Close()
+			if err := os.Remove(f.Name()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				tb.Errorf("failed to remove temp file %s: %s", f.Name(), err)
+			}
+			if _, err := os.Stat(f.Name()); !errors.Is(err, fs.ErrNotExist) {
+				tb.Errorf("temp file leaked: %s", f.Name())
+			}
+		}
+	})
+	return tr
+}
+
+// Create creates a new temp file in the default temp directory, see
+// os.CreateTemp for how pattern is used. Fatals on error.
+func (tr *TempFileTracker) Create(pattern string) *os.File {
+	tr.tb.Helper()
+
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		tr.tb.Fatalf("failed to create temp file: %s", err)
+	}
+	tr.files = append(tr.files, f)
+	return f
+}
@@ -0,0 +1,104 @@
+package testingt_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestCopyDir(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "top.txt"), "top", 0o644)
+	writeFile(t, filepath.Join(src, "run.sh"), "#!/bin/sh", 0o755)
+	require.NoError(t, os.Mkdir(filepath.Join(src, "nested"), 0o750))
+	writeFile(t, filepath.Join(src, "nested", "secret.txt"), "shhh", 0o600)
+
+	dst := testingt.CopyDir(t, src)
+	require.NotEqual(t, src, dst)
+
+	tests := []struct {
+		path     string
+		contents string
+		mode     fs.FileMode
+	}{
+		{path: "top.txt", contents: "top", mode: 0o644},
+		{path: "run.sh", contents: "#!/bin/sh", mode: 0o755},
+		{path: filepath.Join("nested", "secret.txt"), contents: "shhh", mode: 0o600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path := filepath.Join(dst, tt.path)
+
+			b, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.contents, string(b))
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, info.Mode().Perm())
+		})
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "nested"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, fs.FileMode(0o750), info.Mode().Perm())
+}
+
+func writeFile(t *testing.T, path, contents string, perm fs.FileMode) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(contents), perm))
+	require.NoError(t, os.Chmod(path, perm))
+}
+
+func TestLoadKeysFile(t *testing.T) {
+	t.Run("skips comments and blank lines", func(t *testing.T) {
+		var s testingt.Store
+		testingt.LoadKeysFile(t, &s, filepath.Join("testdata", "keys.txt"))
+
+		assert.Equal(t, []string{"first", "second", "third"}, s.Keys())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.LoadKeysFile(tb, &s, filepath.Join("testdata", "does-not-exist.txt"))
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "failed to open keys file")
+	})
+}
+
+func TestTrackTempFiles(t *testing.T) {
+	var paths []string
+	fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+		tr := testingt.TrackTempFiles(tb)
+		for range 3 {
+			f := tr.Create("tracked-*.txt")
+			_, err := f.WriteString("contents")
+			assert.NoError(t, err)
+			paths = append(paths, f.Name())
+		}
+
+		for _, p := range paths {
+			assert.FileExists(t, p)
+		}
+
+		// removing one out from under the tracker is fine too
+		assert.NoError(t, os.Remove(paths[0]))
+	})
+
+	require.False(t, fake.Failed(), fake.Errors())
+	require.Len(t, paths, 3)
+	for _, p := range paths {
+		assert.NoFileExists(t, p)
+	}
+}
@@ -0,0 +1,35 @@
+package testingt
+
+import "testing"
+
+// FlakeCheck runs body runs times, counting the runs where body reports
+// false or fails, and logs the resulting flake percentage. Each run gets a
+// FakeTB of its own, so a failing run, Fatal and require included, is
+// counted rather than failing the test. It only fails when every run
+// fails, the point is to quantify flakiness rather than gate on it. The
+// flake percentage is returned as well. A skipped run counts as a pass.
+func FlakeCheck(tb testing.TB, runs int, body func(tb testing.TB) bool) float64 {
+	tb.Helper()
+
+	if runs < 1 {
+		tb.Fatalf("runs must be at least 1, got %d", runs)
+	}
+
+	var failures int
+	for range runs {
+		var ok bool
+		fake := RunFakeTB(tb, func(tb testing.TB) {
+			ok = body(tb)
+		})
+		if fake.Failed() || (!ok && !fake.Skipped()) {
+			failures++
+		}
+	}
+
+	rate := float64(failures) / float64(runs) * 100
+	logf(tb, "flake rate: %d/%d runs failed (%.1f%%)", failures, runs, rate)
+	if failures == runs {
+		tb.Errorf("all %d runs failed", runs)
+	}
+	return rate
+}
@@ -0,0 +1,61 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestFlakeCheck(t *testing.T) {
+	t.Run("deterministically flaky body", func(t *testing.T) {
+		var calls int
+		everyFourthFails := func(tb testing.TB) bool {
+			calls++
+			return calls%4 != 0
+		}
+
+		var rate float64
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			rate = testingt.FlakeCheck(tb, 20, everyFourthFails)
+		})
+
+		require.False(t, fake.Failed())
+		assert.Equal(t, 25.0, rate)
+		assert.Equal(t, []string{"flake rate: 5/20 runs failed (25.0%)"}, fake.Logs())
+	})
+
+	t.Run("always failing body fails", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.FlakeCheck(tb, 3, func(tb testing.TB) bool { return false })
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"all 3 runs failed"}, fake.Errors())
+	})
+	t.Run("failing runs are counted, not fatal", func(t *testing.T) {
+		var calls int
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.FlakeCheck(tb, 4, func(tb testing.TB) bool {
+				calls++
+				require.NotEqual(tb, 1, calls%2, "odd runs fail")
+				return true
+			})
+		})
+
+		assert.False(t, fake.Failed(), fake.Errors())
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, []string{"flake rate: 2/4 runs failed (50.0%)"}, fake.Logs())
+	})
+
+	t.Run("no runs", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.FlakeCheck(tb, 0, func(tb testing.TB) bool { return true })
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"runs must be at least 1, got 0"}, fake.Errors())
+	})
+}
@@ -0,0 +1,111 @@
+package testingt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// updateFlag is the flag that has the golden helpers write golden files
+// rather than compare against them, e.g. go test ./... -testingt.update.
+// It's namespaced so it doesn't clash with a test package defining the
+// customary -update flag of its own, and only registered in test binaries
+// so it doesn't leak into the flags of a program importing this package.
+const updateFlag = "testingt.update"
+
+var update = new(bool)
+
+func init() {
+	if testing.Testing() {
+		flag.BoolVar(update, updateFlag, false, "update golden files under testdata instead of comparing against them")
+	}
+}
+
+// goldenPath returns the path of the golden file for name, under testdata.
+func goldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// readGolden returns the contents of the golden file at path. With
+// -testingt.update, got is written to path first.
+func readGolden(tb testing.TB, path string, got []byte) []byte {
+	tb.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("failed to create golden dir: %s", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("failed to update golden file: %s", err)
+		}
+		logf(tb, "updated golden file %s", path)
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		tb.Fatalf("golden file %s does not exist, run with -testingt.update to create it", path)
+	}
+	if err != nil {
+		tb.Fatalf("failed to read golden file: %s", err)
+	}
+	return want
+}
+
+// GoldenSequence compares got against the newline delimited golden file
+// testdata/<name>.golden, one element per line, and fatals on any
+// difference. Run the tests with -testingt.update to write got as the new
+// golden file. Pairs nicely with OrderedStore.Keys.
+func GoldenSequence(tb testing.TB, name string, got []string) {
+	tb.Helper()
+
+	var b strings.Builder
+	for _, s := range got {
+		b.WriteString(s + "\n")
+	}
+
+	path := goldenPath(name)
+	raw := string(readGolden(tb, path, []byte(b.String())))
+	want := strings.Split(strings.TrimSuffix(raw, "\n"), "\n")
+	if raw == "" {
+		want = nil
+	}
+
+	if !slices.Equal(got, want) {
+		tb.Fatalf("sequence does not match golden file %s, run with -testingt.update if this is expected:\n\tgot:  %q\n\twant: %q", path, got, want)
+	}
+}
+
+// GoldenResponse issues a method request for path against h and compares
+// the JSON response body to the golden file testdata/<test name>.golden,
+// fataling on any difference. Subtest names have their slashes replaced
+// with underscores, so it's one golden response per test. The body is
+// indented before comparing, keeping golden files readable and diffs
+// meaningful. Run the tests with -testingt.update to write the response as
+// the new golden file.
+func GoldenResponse(tb testing.TB, h http.Handler, method, path string) {
+	tb.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	var got bytes.Buffer
+	if err := json.Indent(&got, rec.Body.Bytes(), "", "\t"); err != nil {
+		tb.Fatalf("response to %s %s is not valid JSON: %s\n\tbody: %s", method, path, err, rec.Body)
+	}
+	got.WriteByte('\n')
+
+	golden := goldenPath(strings.ReplaceAll(tb.Name(), "/", "_"))
+	want := readGolden(tb, golden, got.Bytes())
+	if !bytes.Equal(got.Bytes(), want) {
+		tb.Fatalf("response to %s %s does not match golden file %s, run with -testingt.update if this is expected:\n\tgot:\n%s\n\twant:\n%s", method, path, golden, got.Bytes(), want)
+	}
+}
@@ -0,0 +1,117 @@
+package testingt_test
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestGoldenSequence(t *testing.T) {
+	newOrdered := func(t *testing.T, keys ...string) *testingt.OrderedStore {
+		var s testingt.OrderedStore
+		for _, k := range keys {
+			require.NoError(t, s.Add(k))
+		}
+		return &s
+	}
+
+	t.Run("match", func(t *testing.T) {
+		s := newOrdered(t, "third", "first", "second")
+
+		testingt.GoldenSequence(t, "ordered_keys", s.Keys())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		setUpdate(t, false)
+		s := newOrdered(t, "first", "second", "third")
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.GoldenSequence(tb, "ordered_keys", s.Keys())
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "sequence does not match golden file testdata/ordered_keys.golden")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		setUpdate(t, true)
+		name := "update_" + filepath.Base(t.TempDir())
+		t.Cleanup(func() { os.Remove(filepath.Join("testdata", name+".golden")) })
+
+		s := newOrdered(t, "b", "a")
+		testingt.GoldenSequence(t, name, s.Keys())
+
+		b, err := os.ReadFile(filepath.Join("testdata", name+".golden"))
+		require.NoError(t, err)
+		assert.Equal(t, "b\na\n", string(b))
+	})
+}
+
+// setUpdate runs the rest of the test as if -testingt.update was set to v.
+// Tests asserting a mismatch set it to false, so running the suite with
+// -testingt.update doesn't overwrite golden files with their deliberately
+// wrong output.
+func setUpdate(t *testing.T, v bool) {
+	t.Helper()
+
+	f := flag.Lookup("testingt.update")
+	require.NotNil(t, f, "the -testingt.update flag should be registered")
+	prev := f.Value.String()
+	require.NoError(t, f.Value.Set(strconv.FormatBool(v)))
+	t.Cleanup(func() { f.Value.Set(prev) })
+}
+
+func TestGoldenResponse(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "second", "first")
+
+		testingt.GoldenResponse(t, testingt.Handler(&s), http.MethodGet, "/keys")
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		var s testingt.Store
+
+		testingt.GoldenResponse(t, testingt.Handler(&s), http.MethodGet, "/keys")
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		setUpdate(t, false)
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "other")
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.GoldenResponse(tb, testingt.Handler(&s), http.MethodGet, "/keys")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "response to GET /keys does not match golden file testdata/TestGoldenResponse_mismatch.golden")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		setUpdate(t, true)
+		golden := filepath.Join("testdata", "TestGoldenResponse_update.golden")
+		t.Cleanup(func() { os.Remove(golden) })
+
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "first")
+		testingt.GoldenResponse(t, testingt.Handler(&s), http.MethodGet, "/keys")
+
+		b, err := os.ReadFile(golden)
+		require.NoError(t, err)
+		assert.Equal(t, "[\n\t\"first\"\n]\n", string(b))
+	})
+}
+
+func TestUpdateFlag(t *testing.T) {
+	assert.NotNil(t, flag.Lookup("testingt.update"))
+	assert.Nil(t, flag.Lookup("update"), "the customary -update flag should be left for test packages to define")
+}
@@ -0,0 +1,227 @@
+package testingt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// The harnesses in this file each answer a question about how the testing
+// package behaves by running it, rather than asking you to take the docs'
+// word for it. Their tests pin the answers down.
+
+const (
+	// cleanupOnPanicEnv is set in the child test process CleanupOnPanic
+	// runs the panic in.
+	cleanupOnPanicEnv = "TESTINGT_CLEANUP_ON_PANIC"
+	// cleanupOnPanicMarker is printed by the panicking child's cleanup.
+	cleanupOnPanicMarker = "testingt: cleanup ran after panic"
+)
+
+// CleanupOnPanic reports whether a cleanup registered in a test runs when
+// that test panics and nothing recovers it.
+//
+// An unrecovered panic takes down the whole test binary, so t's test is
+// rerun in a child process of the same test binary. In the child, the call
+// registers a cleanup printing a marker and panics, never returning. In the
+// parent, it reports whether the marker made it into the child's output.
+// The child reruns all of t's test, so call it before anything else.
+func CleanupOnPanic(t *testing.T) bool {
+	t.Helper()
+
+	if os.Getenv(cleanupOnPanicEnv) == "1" {
+		t.Cleanup(func() { fmt.Println(cleanupOnPanicMarker) })
+		panic("boom")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run="+runPattern(t.Name()))
+	cmd.Env = append(os.Environ(), cleanupOnPanicEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("panicking child test should fail, got err %v:\n%s", err, out)
+	}
+	logf(t, "child test output:\n%s", out)
+	return strings.Contains(string(out), cleanupOnPanicMarker)
+}
+
+// runPattern returns the -test.run pattern matching the test named name,
+// and only that test, anchoring each subtest level on its own.
+func runPattern(name string) string {
+	levels := strings.Split(name, "/")
+	for i, l := range levels {
+		levels[i] = "^" + regexp.QuoteMeta(l) + "$"
+	}
+	return strings.Join(levels, "/")
+}
+
+// CleanupRunsOnSkip reports whether a cleanup registered in a subtest before
+// it calls t.Skip still runs. It does: Skip ends the test via
+// runtime.Goexit, the same as FailNow, and cleanups always run once the
+// test's func is done, however it got there.
+func CleanupRunsOnSkip(t *testing.T) bool {
+	t.Helper()
+
+	var ran bool
+	t.Run("skipped subtest", func(t *testing.T) {
+		t.Cleanup(func() { ran = true })
+		t.Skip("skipping after registering a cleanup")
+	})
+	return ran
+}
+
+// maxHelperChainDepth is the number of distinct helper funcs, helperChain1
+// through helperChain6, a helper chain can be built from.
+const maxHelperChainDepth = 6
+
+// HelperChainFrame calls a chain of depth distinct helper funcs, the top one
+// first, with the last one calling Fatal, against a FakeTB, and returns the
+// frame the fatal was attributed to. Every level calls t.Helper except the
+// omit'th, counting from 1 at the top, pass 0 to omit none. FakeTB marks
+// helpers by function, so the levels must be distinct funcs rather than one
+// recursive closure for the chain to prove anything.
+func HelperChainFrame(tb testing.TB, depth, omit int) runtime.Frame {
+	tb.Helper()
+
+	if depth < 1 || depth > maxHelperChainDepth {
+		tb.Fatalf("helper chain depth must be between 1 and %d, got %d", maxHelperChainDepth, depth)
+	}
+
+	fake := RunFakeTB(tb, func(tb testing.TB) {
+		helperChain1(tb, depth, omit)
+	})
+
+	frames := fake.ErrorFrames()
+	if len(frames) != 1 {
+		tb.Fatalf("expected exactly 1 error from the helper chain, got %d", len(frames))
+	}
+	return frames[0]
+}
+
+// RequireHelperChain fatals unless a fatal at the bottom of a chain of depth
+// distinct helper funcs, each calling t.Helper, is attributed to the func
+// calling into the top of the chain, i.e. that t.Helper is transitive all
+// the way up. See HelperChainFrame.
+func RequireHelperChain(tb testing.TB, depth int) {
+	tb.Helper()
+
+	got := HelperChainFrame(tb, depth, 0)
+	// the chain is called from a closure within HelperChainFrame
+	if want := reflect.TypeFor[FakeTB]().PkgPath() + ".HelperChainFrame.func1"; got.Function != want {
+		tb.Fatalf("helper chain of depth %d reported from %s (%s:%d), want %s", depth, got.Function, got.File, got.Line, want)
+	}
+}
+
+func helperChain1(tb testing.TB, n, omit int) {
+	if omit != 1 {
+		tb.Helper()
+	}
+	if n <= 1 {
+		tb.Fatal("bottom of the helper chain")
+	}
+	helperChain2(tb, n-1, omit)
+}
+
+func helperChain2(tb testing.TB, n, omit int) {
+	if omit != 2 {
+		tb.Helper()
+	}
+	if n <= 1 {
+		tb.Fatal("bottom of the helper chain")
+	}
+	helperChain3(tb, n-1, omit)
+}
+
+func helperChain3(tb testing.TB, n, omit int) {
+	if omit != 3 {
+		tb.Helper()
+	}
+	if n <= 1 {
+		tb.Fatal("bottom of the helper chain")
+	}
+	helperChain4(tb, n-1, omit)
+}
+
+func helperChain4(tb testing.TB, n, omit int) {
+	if omit != 4 {
+		tb.Helper()
+	}
+	if n <= 1 {
+		tb.Fatal("bottom of the helper chain")
+	}
+	helperChain5(tb, n-1, omit)
+}
+
+func helperChain5(tb testing.TB, n, omit int) {
+	if omit != 5 {
+		tb.Helper()
+	}
+	if n <= 1 {
+		tb.Fatal("bottom of the helper chain")
+	}
+	helperChain6(tb, n-1, omit)
+}
+
+func helperChain6(tb testing.TB, _, omit int) {
+	if omit != 6 {
+		tb.Helper()
+	}
+	tb.Fatal("bottom of the helper chain")
+}
+
+// ParentWaitsForParallelCleanups reports whether a parent test's cleanups
+// wait on the cleanups of its parallel subtests. They do: parallel subtests
+// only start once the parent's func returns, and the parent's cleanups only
+// run once every subtest, cleanups included, has finished.
+func ParentWaitsForParallelCleanups(t *testing.T) bool {
+	t.Helper()
+
+	const subtests = 4
+
+	var (
+		counter atomic.Int32
+		waited  bool
+	)
+	t.Run("parent", func(t *testing.T) {
+		t.Cleanup(func() {
+			waited = counter.Load() == subtests
+			if !waited {
+				t.Errorf("parent cleanup ran after %d of %d subtest cleanups", counter.Load(), subtests)
+			}
+		})
+
+		for i := range subtests {
+			t.Run("parallel "+strconv.Itoa(i), func(t *testing.T) {
+				t.Parallel()
+				t.Cleanup(func() { counter.Add(1) })
+			})
+		}
+	})
+	return waited
+}
+
+// NestedCleanupWorks reports how many of the cleanups in a subtest ran, when
+// the one cleanup registered by the subtest's func registers another while
+// it runs. Both do, so it returns 2: the testing package keeps popping
+// cleanups until there are none left, so one registered by a cleanup runs
+// straight after it, ahead of any cleanups registered before it.
+func NestedCleanupWorks(t *testing.T) int {
+	t.Helper()
+
+	var ran int
+	t.Run("nested cleanup", func(t *testing.T) {
+		t.Cleanup(func() {
+			ran++
+			t.Cleanup(func() { ran++ })
+		})
+	})
+	return ran
+}
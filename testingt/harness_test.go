@@ -0,0 +1,56 @@
+package testingt_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestCleanupOnPanic(t *testing.T) {
+	assert.True(t, testingt.CleanupOnPanic(t), "cleanup should run when a subtest panics")
+}
+
+func TestCleanupRunsOnSkip(t *testing.T) {
+	assert.True(t, testingt.CleanupRunsOnSkip(t), "cleanup should run when a subtest is skipped")
+}
+
+func TestRequireHelperChain(t *testing.T) {
+	for _, depth := range []int{1, 3, 5} {
+		t.Run(strconv.Itoa(depth), func(t *testing.T) {
+			testingt.RequireHelperChain(t, depth)
+		})
+	}
+
+	t.Run("level without Helper is reported", func(t *testing.T) {
+		frame := testingt.HelperChainFrame(t, 5, 3)
+
+		assert.True(t, strings.HasSuffix(frame.Function, ".helperChain3"), frame.Function)
+	})
+
+	t.Run("bottom without Helper is reported", func(t *testing.T) {
+		frame := testingt.HelperChainFrame(t, 3, 3)
+
+		assert.True(t, strings.HasSuffix(frame.Function, ".helperChain3"), frame.Function)
+	})
+
+	t.Run("depth out of range", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireHelperChain(tb, 0)
+		})
+
+		assert.True(t, fake.Failed())
+		assert.Equal(t, []string{"helper chain depth must be between 1 and 6, got 0"}, fake.Errors())
+	})
+}
+
+func TestParentWaitsForParallelCleanups(t *testing.T) {
+	assert.True(t, testingt.ParentWaitsForParallelCleanups(t), "parent cleanup should wait on parallel subtest cleanups")
+}
+
+func TestNestedCleanupWorks(t *testing.T) {
+	assert.Equal(t, 2, testingt.NestedCleanupWorks(t), "cleanup registered by a cleanup should run")
+}
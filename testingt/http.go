@@ -0,0 +1,103 @@
+package testingt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Handler exposes s over HTTP, making for a tiny test server when handed to
+// httptest.NewServer:
+//
+//	POST   /keys/{k}  adds k, 200 OK
+//	DELETE /keys/{k}  removes k, 204 No Content or 404 Not Found if absent
+//	GET    /keys      lists the keys as a sorted JSON array
+//
+// Errors are reported as a JSON object, {"error": "..."}, an empty key
+// being a 400 Bad Request.
+//
+// A Store isn't safe for concurrent use, so requests are serialized by the
+// handler. Don't mutate s directly while the handler is serving.
+func Handler(s *Store) http.Handler {
+	h := &storeHandler{store: s}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /keys/{k}", h.add)
+	mux.HandleFunc("DELETE /keys/{k}", h.rm)
+	// a wildcard never matches an empty segment, these catch /keys/ so an
+	// empty key gets a 400 rather than a 404
+	mux.HandleFunc("POST /keys/{$}", h.add)
+	mux.HandleFunc("DELETE /keys/{$}", h.rm)
+	mux.HandleFunc("GET /keys", h.list)
+	return mux
+}
+
+type storeHandler struct {
+	mu    sync.Mutex
+	store *Store
+}
+
+func (h *storeHandler) add(w http.ResponseWriter, r *http.Request) {
+	k := r.PathValue("k")
+	if k == "" {
+		writeError(w, ErrEmptyKey, http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	err := h.store.Add(k)
+	h.mu.Unlock()
+
+	switch {
+	case errors.Is(err, ErrCapacityExceeded):
+		writeError(w, err, http.StatusInsufficientStorage)
+	case err != nil:
+		writeError(w, err, http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *storeHandler) rm(w http.ResponseWriter, r *http.Request) {
+	k := r.PathValue("k")
+	if k == "" {
+		writeError(w, ErrEmptyKey, http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	found := h.store.Has(k)
+	h.store.Rm(k)
+	h.mu.Unlock()
+
+	if !found {
+		writeError(w, ErrKeyNotFound, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *storeHandler) list(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	b, err := h.store.MarshalJSON()
+	h.mu.Unlock()
+
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// errorResponse is the body of a Handler error response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, err error, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
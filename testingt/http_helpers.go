@@ -0,0 +1,251 @@
+package testingt
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NewTestServer starts an httptest.Server serving Handler(s) and closes it
+// once the test is done.
+func NewTestServer(tb testing.TB, s *Store) *httptest.Server {
+	tb.Helper()
+
+	srv := httptest.NewServer(Handler(s))
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+// recordedExchange is a request and the response it's expected to get, as
+// stored in a ReplayRequests fixture.
+type recordedExchange struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Body     string `json:"body,omitempty"`
+	Status   int    `json:"status"`
+	Response string `json:"response,omitempty"`
+}
+
+// ReplayRequests replays the recorded exchanges in the JSON fixture file at
+// path against handler, in order, and fails for every response whose status
+// or body doesn't match the recording. The fixture is an array of
+// exchanges:
+//
+//	[
+//		{"method": "POST", "path": "/keys/first", "status": 200},
+//		{"method": "GET", "path": "/keys", "status": 200, "response": "[\"first\"]"}
+//	]
+//
+// Requests can carry a "body". Response bodies are compared with surrounding
+// whitespace trimmed, so a trailing newline from a json.Encoder doesn't
+// count. Exchanges are replayed against the same handler, so each one sees
+// the state left by those before it.
+func ReplayRequests(tb testing.TB, handler http.Handler, path string) {
+	tb.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("failed to read fixture: %s", err)
+	}
+	var exchanges []recordedExchange
+	if err := json.Unmarshal(b, &exchanges); err != nil {
+		tb.Fatalf("failed to decode fixture %s: %s", path, err)
+	}
+
+	for i, ex := range exchanges {
+		req := httptest.NewRequest(ex.Method, ex.Path, strings.NewReader(ex.Body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != ex.Status {
+			tb.Errorf("exchange %d, %s %s: got status %d, want %d", i, ex.Method, ex.Path, rec.Code, ex.Status)
+		}
+		if got, want := strings.TrimSpace(rec.Body.String()), strings.TrimSpace(ex.Response); got != want {
+			tb.Errorf("exchange %d, %s %s: unexpected response body:\n\tgot:  %s\n\twant: %s", i, ex.Method, ex.Path, got, want)
+		}
+	}
+}
+
+// RequireStatus issues a method request for path against h and fatals,
+// printing the response body, unless it responds with the want status.
+func RequireStatus(tb testing.TB, h http.Handler, method, path string, want int) {
+	tb.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	if rec.Code != want {
+		tb.Fatalf("%s %s: got status %d, want %d\n\tbody: %s", method, path, rec.Code, want, strings.TrimSpace(rec.Body.String()))
+	}
+}
+
+// LogRequests wraps next, logging the method, path, and response status of
+// every request it serves to tb. Wrap a handler with it when an HTTP test
+// fails and it isn't clear which request went wrong. Logging is explicitly
+// asked for here, so it's unaffected by SetVerbose.
+func LogRequests(tb testing.TB, next http.Handler) http.Handler {
+	tb.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		tb.Logf("%s %s: %d", r.Method, r.URL.Path, sw.status)
+	})
+}
+
+// statusWriter records the status code written through it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wrote {
+		w.status, w.wrote = code, true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RequireConcurrentHTTPConsistent fires n concurrent POSTs of distinct keys
+// at srv, a server for a Handler, and then fatals unless listing the keys
+// shows every one of them. Run it with -race to check the handler's locking
+// along the way.
+func RequireConcurrentHTTPConsistent(tb testing.TB, srv *httptest.Server, n int) {
+	tb.Helper()
+
+	client := srv.Client()
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k := "concurrent-" + strconv.Itoa(i)
+			resp, err := client.Post(srv.URL+"/keys/"+k, "", nil)
+			if err != nil {
+				tb.Errorf("failed to add key %q: %s", k, err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				tb.Errorf("adding key %q: got status %d, want %d", k, resp.StatusCode, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+	if tb.Failed() {
+		tb.FailNow()
+	}
+
+	keys := FetchKeys(tb, srv)
+	var missing []string
+	for i := range n {
+		k := "concurrent-" + strconv.Itoa(i)
+		if _, found := slices.BinarySearch(keys, k); !found {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		tb.Fatalf("%d of %d concurrently added keys missing: %q", len(missing), n, missing)
+	}
+}
+
+// FetchKeys GETs /keys from srv, a server for a Handler, and returns the
+// listed keys sorted. Fatals on any transport, status, or decoding error.
+func FetchKeys(tb testing.TB, srv *httptest.Server) []string {
+	tb.Helper()
+
+	resp, err := srv.Client().Get(srv.URL + "/keys")
+	if err != nil {
+		tb.Fatalf("failed to list keys: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		tb.Fatalf("listing keys: got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var keys []string
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		tb.Fatalf("failed to decode keys: %s", err)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// RequireBadRequest issues a method request for path against h and fatals
+// unless it's rejected with a 400 Bad Request carrying a JSON error body,
+// the way Handler reports errors.
+func RequireBadRequest(tb testing.TB, h http.Handler, method, path string) {
+	tb.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	body := strings.TrimSpace(rec.Body.String())
+	if rec.Code != http.StatusBadRequest {
+		tb.Fatalf("%s %s: got status %d, want %d\n\tbody: %s", method, path, rec.Code, http.StatusBadRequest, body)
+	}
+	var resp errorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == "" {
+		tb.Fatalf("%s %s: 400 body is not a JSON error: %s", method, path, body)
+	}
+}
+
+// RequireHandlerRespectsCancel serves h a GET /keys request whose context is
+// already cancelled and fatals unless h returns within the same grace period
+// RequireRespectsCancel allows. How h responds is up to it, what matters is
+// that a slow handler stops working for a client that's gone.
+func RequireHandlerRespectsCancel(tb testing.TB, h http.Handler) {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/keys", nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(cancelTimeout):
+		tb.Fatalf("handler did not return within %s of cancellation", cancelTimeout)
+	}
+}
+
+// RequireContentType issues a method request for path against h and fatals
+// unless the response's Content-Type has the want media type. Parameters
+// such as charset are ignored, "application/json; charset=utf-8" matches a
+// want of "application/json".
+func RequireContentType(tb testing.TB, h http.Handler, method, path, want string) {
+	tb.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	got := rec.Header().Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(got); err != nil || mediaType != want {
+		tb.Fatalf("%s %s: got Content-Type %q, want %q", method, path, got, want)
+	}
+}
@@ -0,0 +1,246 @@
+package testingt_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestNewTestServer(t *testing.T) {
+	var s testingt.Store
+	srv := testingt.NewTestServer(t, &s)
+
+	resp, err := srv.Client().Post(srv.URL+"/keys/first", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = srv.Client().Get(srv.URL + "/keys")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `["first"]`, string(body))
+}
+
+func TestReplayRequests(t *testing.T) {
+	t.Run("matching recording", func(t *testing.T) {
+		var s testingt.Store
+		testingt.ReplayRequests(t, testingt.Handler(&s), "testdata/replay_add_list.json")
+
+		assert.Equal(t, []string{"first", "second"}, s.Keys())
+	})
+
+	t.Run("mismatching recording", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fixture.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[
+			{"method": "POST", "path": "/keys/first", "status": 201},
+			{"method": "GET", "path": "/keys", "status": 200, "response": "[]"}
+		]`), 0o644))
+
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.ReplayRequests(tb, testingt.Handler(&s), path)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			"exchange 0, POST /keys/first: got status 200, want 201",
+			"exchange 1, GET /keys: unexpected response body:\n\tgot:  [\"first\"]\n\twant: []",
+		}, fake.Errors())
+	})
+
+	t.Run("missing fixture", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.ReplayRequests(tb, http.NotFoundHandler(), "testdata/does_not_exist.json")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "failed to read fixture")
+	})
+}
+
+func TestRequireStatus(t *testing.T) {
+	t.Run("ok on add", func(t *testing.T) {
+		var s testingt.Store
+		testingt.RequireStatus(t, testingt.Handler(&s), http.MethodPost, "/keys/first", http.StatusOK)
+	})
+
+	t.Run("not found on removing an absent key", func(t *testing.T) {
+		var s testingt.Store
+		testingt.RequireStatus(t, testingt.Handler(&s), http.MethodDelete, "/keys/missing", http.StatusNotFound)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		var s testingt.Store
+		testingt.RequireStatus(t, testingt.Handler(&s), http.MethodPut, "/keys/first", http.StatusMethodNotAllowed)
+	})
+
+	t.Run("mismatch prints the body", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStatus(tb, testingt.Handler(&s), http.MethodDelete, "/keys/missing", http.StatusNoContent)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"DELETE /keys/missing: got status 404, want 204\n\tbody: {\"error\":\"key not found\"}"}, fake.Errors())
+	})
+}
+
+func TestLogRequests(t *testing.T) {
+	var s testingt.Store
+	fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+		h := testingt.LogRequests(tb, testingt.Handler(&s))
+		testingt.RequireStatus(tb, h, http.MethodPost, "/keys/first", http.StatusOK)
+		testingt.RequireStatus(tb, h, http.MethodGet, "/keys", http.StatusOK)
+		testingt.RequireStatus(tb, h, http.MethodDelete, "/keys/missing", http.StatusNotFound)
+	})
+
+	assert.False(t, fake.Failed(), fake.Errors())
+	assert.Equal(t, []string{
+		"POST /keys/first: 200",
+		"GET /keys: 200",
+		"DELETE /keys/missing: 404",
+	}, fake.Logs())
+}
+
+func TestRequireConcurrentHTTPConsistent(t *testing.T) {
+	// run with -race to make the most of this one
+	var s testingt.Store
+	srv := testingt.NewTestServer(t, &s)
+
+	testingt.RequireConcurrentHTTPConsistent(t, srv, 50)
+
+	assert.Equal(t, 50, s.Len())
+}
+
+func TestFetchKeys(t *testing.T) {
+	t.Run("seeded via POSTs", func(t *testing.T) {
+		var s testingt.Store
+		srv := testingt.NewTestServer(t, &s)
+		for _, k := range []string{"third", "first", "second"} {
+			resp, err := srv.Client().Post(srv.URL+"/keys/"+k, "", nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		assert.Equal(t, []string{"first", "second", "third"}, testingt.FetchKeys(t, srv))
+	})
+
+	t.Run("not a store server", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(srv.Close)
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.FetchKeys(tb, srv)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"listing keys: got status 404, want 200"}, fake.Errors())
+	})
+}
+
+func TestRequireBadRequest(t *testing.T) {
+	t.Run("empty key", func(t *testing.T) {
+		var s testingt.Store
+		h := testingt.Handler(&s)
+
+		testingt.RequireBadRequest(t, h, http.MethodPost, "/keys/")
+		testingt.RequireBadRequest(t, h, http.MethodDelete, "/keys/")
+		assert.Zero(t, s.Len())
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireBadRequest(tb, testingt.Handler(&s), http.MethodPost, "/keys/first")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"POST /keys/first: got status 200, want 400\n\tbody: "}, fake.Errors())
+	})
+
+	t.Run("400 without a JSON error", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad", http.StatusBadRequest)
+		})
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireBadRequest(tb, h, http.MethodPost, "/keys/")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "POST /keys/: 400 body is not a JSON error")
+	})
+}
+
+func TestRequireHandlerRespectsCancel(t *testing.T) {
+	t.Run("store handler", func(t *testing.T) {
+		var s testingt.Store
+		testingt.RequireHandlerRespectsCancel(t, testingt.Handler(&s))
+	})
+
+	t.Run("slow handler watching the context", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+				// nginx's 499, the client closed the request
+				w.WriteHeader(499)
+			case <-time.After(time.Minute):
+			}
+		})
+
+		testingt.RequireHandlerRespectsCancel(t, h)
+	})
+
+	t.Run("slow handler ignoring the context", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		})
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireHandlerRespectsCancel(tb, h)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"handler did not return within 100ms of cancellation"}, fake.Errors())
+	})
+}
+
+func TestRequireContentType(t *testing.T) {
+	var s testingt.Store
+	h := testingt.Handler(&s)
+
+	t.Run("list endpoint", func(t *testing.T) {
+		testingt.RequireContentType(t, h, http.MethodGet, "/keys", "application/json")
+	})
+
+	t.Run("parameters are ignored", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		})
+
+		testingt.RequireContentType(t, h, http.MethodGet, "/keys", "application/json")
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireContentType(tb, h, http.MethodGet, "/keys", "text/plain")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`GET /keys: got Content-Type "application/json", want "text/plain"`}, fake.Errors())
+	})
+}
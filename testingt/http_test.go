@@ -0,0 +1,102 @@
+package testingt_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestHandler(t *testing.T) {
+	do := func(t *testing.T, srv *httptest.Server, method, path string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(method, srv.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("POST adds the key", func(t *testing.T) {
+		var s testingt.Store
+		srv := httptest.NewServer(testingt.Handler(&s))
+		defer srv.Close()
+
+		status, _ := do(t, srv, http.MethodPost, "/keys/first")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, s.Has("first"))
+	})
+
+	t.Run("POST past capacity", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithCapacity(1))
+		require.NoError(t, s.Add("first"))
+		srv := httptest.NewServer(testingt.Handler(s))
+		defer srv.Close()
+
+		status, _ := do(t, srv, http.MethodPost, "/keys/second")
+
+		assert.Equal(t, http.StatusInsufficientStorage, status)
+		assert.False(t, s.Has("second"))
+	})
+
+	t.Run("DELETE removes the key", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("first"))
+		srv := httptest.NewServer(testingt.Handler(&s))
+		defer srv.Close()
+
+		status, _ := do(t, srv, http.MethodDelete, "/keys/first")
+
+		assert.Equal(t, http.StatusNoContent, status)
+		assert.False(t, s.Has("first"))
+	})
+
+	t.Run("DELETE of a missing key is a 404", func(t *testing.T) {
+		var s testingt.Store
+		srv := httptest.NewServer(testingt.Handler(&s))
+		defer srv.Close()
+
+		status, _ := do(t, srv, http.MethodDelete, "/keys/missing")
+
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("GET lists sorted keys", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "third", "first", "second")
+		srv := httptest.NewServer(testingt.Handler(&s))
+		defer srv.Close()
+
+		status, body := do(t, srv, http.MethodGet, "/keys")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `["first","second","third"]`, body)
+	})
+}
+
+func TestHandler_EmptyKey(t *testing.T) {
+	var s testingt.Store
+	srv := testingt.NewTestServer(t, &s)
+
+	resp, err := srv.Client().Post(srv.URL+"/keys/", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"error": "empty key"}`, string(body))
+	assert.Zero(t, s.Len())
+}
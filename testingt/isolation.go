@@ -0,0 +1,91 @@
+package testingt
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RequireUniqueTempDirs runs a subtest per name, each grabbing its own
+// t.TempDir, and fails unless every subtest was handed a distinct dir.
+func RequireUniqueTempDirs(t *testing.T, subtests ...string) {
+	t.Helper()
+
+	owners := make(map[string]string, len(subtests))
+	for _, name := range subtests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if owner, ok := owners[dir]; ok {
+				t.Fatalf("temp dir %s shared with subtest %s", dir, owner)
+			}
+			owners[dir] = t.Name()
+		})
+	}
+}
+
+// RequireEnvIsolated proves t.Setenv doesn't leak between sequential
+// subtests: the first subtest sets the env var name, the second fails if
+// name doesn't hold whatever it held before the first ran (absent, for an
+// unset var).
+func RequireEnvIsolated(t *testing.T, name string) {
+	t.Helper()
+
+	origVal, origOK := os.LookupEnv(name)
+	t.Run("sets "+name, func(t *testing.T) {
+		t.Setenv(name, "leaked from "+t.Name())
+	})
+	t.Run("does not see "+name, func(t *testing.T) {
+		val, ok := os.LookupEnv(name)
+		if val != origVal || ok != origOK {
+			t.Fatalf("env var %s leaked between subtests: got %q (set=%t), want %q (set=%t)", name, val, ok, origVal, origOK)
+		}
+	})
+}
+
+// distinctTempDirs holds the temp dirs recorded by RequireDistinctTempDirs,
+// keyed by the parent test's name and then by dir, to the subtest that was
+// handed the dir. Entries only live as long as the subtest they belong to.
+var distinctTempDirs = struct {
+	mu     sync.Mutex
+	owners map[string]map[string]string
+}{owners: make(map[string]map[string]string)}
+
+// RequireDistinctTempDirs is called from inside each of a parent's
+// parallel subtests. It records the subtest's t.TempDir in a store shared
+// by all subtests of the same parent, and fatals if a sibling was already
+// handed the same dir. The check runs as each dir is recorded rather than
+// in the parent's cleanup, since a subtest has no way to register a
+// cleanup on its parent's t. Each subtest's cleanup drops its dir again,
+// just before the testing package removes the dir, so entries don't pile
+// up across reruns with -count.
+func RequireDistinctTempDirs(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	parent := t.Name()
+	if i := strings.LastIndex(parent, "/"); i >= 0 {
+		parent = parent[:i]
+	}
+
+	distinctTempDirs.mu.Lock()
+	defer distinctTempDirs.mu.Unlock()
+	owners := distinctTempDirs.owners[parent]
+	if owners == nil {
+		owners = make(map[string]string)
+		distinctTempDirs.owners[parent] = owners
+	}
+	if owner, ok := owners[dir]; ok {
+		t.Fatalf("temp dir %s shared with subtest %s", dir, owner)
+	}
+	owners[dir] = t.Name()
+
+	t.Cleanup(func() {
+		distinctTempDirs.mu.Lock()
+		defer distinctTempDirs.mu.Unlock()
+		delete(owners, dir)
+		if len(owners) == 0 {
+			delete(distinctTempDirs.owners, parent)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireUniqueTempDirs(t *testing.T) {
+	testingt.RequireUniqueTempDirs(t, "first", "second", "third")
+}
+
+func TestRequireEnvIsolated(t *testing.T) {
+	t.Run("unset var", func(t *testing.T) {
+		testingt.RequireEnvIsolated(t, "DEMO_ISOLATED")
+	})
+
+	t.Run("var with an existing value", func(t *testing.T) {
+		t.Setenv("DEMO_ISOLATED", "$TEXAS")
+
+		testingt.RequireEnvIsolated(t, "DEMO_ISOLATED")
+	})
+}
+
+func TestRequireDistinctTempDirs(t *testing.T) {
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testingt.RequireDistinctTempDirs(t)
+		})
+	}
+}
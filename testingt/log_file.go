@@ -0,0 +1,72 @@
+package testingt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// fileLogTB is a testing.TB that mirrors its logs to a file.
+type fileLogTB struct {
+	testing.TB
+
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// LogToFile returns a testing.TB that mirrors everything logged through its
+// Log and Logf to the file at path, creating the file and any missing parent
+// dirs. Point path at a dir your CI collects to keep the logs as an
+// artifact. The file is flushed and closed in cleanup.
+//
+// When quieted with SetVerbose(false), logs only go to the file.
+func LogToFile(tb testing.TB, path string) testing.TB {
+	tb.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		tb.Fatalf("failed to create log dir: %s", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		tb.Fatalf("failed to create log file: %s", err)
+	}
+
+	l := &fileLogTB{TB: tb, w: bufio.NewWriter(f)}
+	tb.Cleanup(func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if err := l.w.Flush(); err != nil {
+			tb.Errorf("failed to flush log file %s: %s", path, err)
+		}
+		if err := f.Close(); err != nil {
+			tb.Errorf("failed to close log file %s: %s", path, err)
+		}
+	})
+	return l
+}
+
+func (l *fileLogTB) Log(args ...any) {
+	l.TB.Helper()
+	if !quiet.Load() {
+		l.TB.Log(args...)
+	}
+	l.write(fmt.Sprintln(args...))
+}
+
+func (l *fileLogTB) Logf(format string, args ...any) {
+	l.TB.Helper()
+	if !quiet.Load() {
+		l.TB.Logf(format, args...)
+	}
+	l.write(fmt.Sprintf(format, args...) + "\n")
+}
+
+func (l *fileLogTB) write(s string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// write errors surface when the buffer is flushed in cleanup
+	l.w.WriteString(s)
+}
@@ -0,0 +1,29 @@
+package testingt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestLogToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifacts", "helpers.log")
+
+	fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+		tb = testingt.LogToFile(tb, path)
+		tb.Log("first", "line")
+		tb.Logf("second line: %d", 2)
+		tb.Log("third line")
+	})
+	require.False(t, fake.Failed(), fake.Errors())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line: 2\nthird line\n", string(b))
+	assert.Equal(t, []string{"first line", "second line: 2", "third line"}, fake.Logs(), "logs are still sent to the wrapped TB")
+}
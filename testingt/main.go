@@ -0,0 +1,40 @@
+package testingt
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// Main is meant to be called from TestMain. It runs setup once for the
+// whole package, runs the tests and exits with their code, calling the
+// teardown setup returned before exiting. When setup fails, the error is
+// printed and the binary exits with code 1 without running any tests.
+//
+//	func TestMain(m *testing.M) {
+//		testingt.Main(m, func() (func(), error) {
+//			db, err := startDB()
+//			if err != nil {
+//				return nil, err
+//			}
+//			return db.Stop, nil
+//		})
+//	}
+func Main(m *testing.M, setup func() (teardown func(), err error)) {
+	os.Exit(RunMainWithCode(setup, m.Run))
+}
+
+// RunMainWithCode holds Main's logic, returning the exit code instead of
+// calling os.Exit so it can be asserted in-process. body stands in for
+// m.Run. A nil teardown is fine when there's nothing to undo.
+func RunMainWithCode(setup func() (teardown func(), err error), body func() int) int {
+	teardown, err := setup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup failed: %s\n", err)
+		return 1
+	}
+	if teardown != nil {
+		defer teardown()
+	}
+	return body()
+}
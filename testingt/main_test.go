@@ -0,0 +1,49 @@
+package testingt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRunMainWithCode(t *testing.T) {
+	t.Run("setup fails", func(t *testing.T) {
+		var tornDown, ran bool
+		code := testingt.RunMainWithCode(
+			func() (func(), error) {
+				return func() { tornDown = true }, errors.New("no database")
+			},
+			func() int { ran = true; return 0 },
+		)
+
+		assert.NotZero(t, code)
+		assert.False(t, ran, "body should not run")
+		assert.False(t, tornDown, "teardown should not run")
+	})
+
+	t.Run("body code is returned after teardown", func(t *testing.T) {
+		var order []string
+		code := testingt.RunMainWithCode(
+			func() (func(), error) {
+				order = append(order, "setup")
+				return func() { order = append(order, "teardown") }, nil
+			},
+			func() int { order = append(order, "body"); return 3 },
+		)
+
+		assert.Equal(t, 3, code)
+		assert.Equal(t, []string{"setup", "body", "teardown"}, order)
+	})
+
+	t.Run("nil teardown", func(t *testing.T) {
+		code := testingt.RunMainWithCode(
+			func() (func(), error) { return nil, nil },
+			func() int { return 0 },
+		)
+
+		assert.Zero(t, code)
+	})
+}
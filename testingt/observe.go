@@ -0,0 +1,90 @@
+package testingt
+
+import (
+	"maps"
+	"slices"
+)
+
+// Op is the kind of mutation a Change records.
+type Op string
+
+const (
+	OpAdd Op = "add"
+	OpRm  Op = "rm"
+)
+
+// Change is a single mutation of a Store.
+type Change struct {
+	Op  Op
+	Key string
+}
+
+type observer struct {
+	id int
+	fn func(Change)
+}
+
+// OnChange registers fn to be called with every change to the store. Only
+// mutations that change the store's state are observed, re-adding an
+// existing key or removing an absent one is not a change. Observers are
+// called synchronously, in registration order, before the mutation returns.
+// The returned func unregisters fn.
+func (s *Store) OnChange(fn func(Change)) (unregister func()) {
+	s.observerID++
+	id := s.observerID
+	s.observers = append(s.observers, observer{id: id, fn: fn})
+
+	return func() {
+		for i, o := range s.observers {
+			if o.id == id {
+				s.observers = append(s.observers[:i:i], s.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// replaceState swaps the store's state for next, notifying observers of
+// each key removed and then each key added, in sorted order, as if the
+// swap had been made one Add or Rm at a time.
+func (s *Store) replaceState(next map[string]bool) {
+	prev := s.state
+	s.state = next
+
+	for _, k := range slices.Sorted(maps.Keys(prev)) {
+		if !next[k] {
+			s.notify(Change{Op: OpRm, Key: k})
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(next)) {
+		if !prev[k] {
+			s.notify(Change{Op: OpAdd, Key: k})
+		}
+	}
+}
+
+func (s *Store) notify(c Change) {
+	for _, o := range s.observers {
+		o.fn(c)
+	}
+}
+
+// changeChannelSize is the buffer size of channels returned by ChangeChannel.
+const changeChannelSize = 64
+
+// ChangeChannel returns a buffered channel that receives every change to s.
+// Mutators never block on the channel, once its buffer is full further
+// changes are dropped until it is drained.
+//
+// The channel is never closed, it's garbage collected along with the store
+// once neither is reachable. Don't range over it expecting it to end.
+func ChangeChannel(s *Store) <-chan Change {
+	ch := make(chan Change, changeChannelSize)
+	s.OnChange(func(c Change) {
+		select {
+		case ch <- c:
+		default:
+		}
+	})
+	return ch
+}
@@ -0,0 +1,99 @@
+package testingt
+
+import (
+	"slices"
+	"testing"
+)
+
+// RequireObservedOrder attaches an observer to s for the duration of mutate
+// and fatals unless the changes it observed are exactly want, in order.
+func RequireObservedOrder(tb testing.TB, s *Store, mutate func(), want []Change) {
+	tb.Helper()
+
+	var got []Change
+	unregister := s.OnChange(func(c Change) { got = append(got, c) })
+	mutate()
+	unregister()
+
+	if !slices.Equal(got, want) {
+		tb.Fatalf("unexpected changes observed:\n\tgot:  %v\n\twant: %v", got, want)
+	}
+}
+
+// BalanceTracker counts the adds minus the removes made to a store. See
+// NewBalanceTracker.
+type BalanceTracker struct {
+	balance  int
+	residual int
+}
+
+// NewBalanceTracker observes s for the rest of the test, counting adds minus
+// removes, and registers a cleanup that fails the test unless every add was
+// balanced by a remove. Use Expect to declare keys that are meant to be left
+// behind. Register it before any cleanups that remove keys, cleanups run
+// LIFO, so the check sees their removes.
+func NewBalanceTracker(tb testing.TB, s *Store) *BalanceTracker {
+	tb.Helper()
+
+	b := new(BalanceTracker)
+	unregister := s.OnChange(func(c Change) {
+		switch c.Op {
+		case OpAdd:
+			b.balance++
+		case OpRm:
+			b.balance--
+		}
+	})
+	tb.Cleanup(func() {
+		unregister()
+		if b.balance != b.residual {
+			tb.Errorf("unbalanced store: %d more add(s) than removes, want %d", b.balance, b.residual)
+		}
+	})
+	return b
+}
+
+// Expect declares that n more keys are expected to be added than removed.
+func (b *BalanceTracker) Expect(n int) {
+	b.residual = n
+}
+
+// Balance returns the number of adds minus removes observed so far.
+func (b *BalanceTracker) Balance() int {
+	return b.balance
+}
+
+// syncObserverProbe is the key RequireSyncObserver adds and removes.
+const syncObserverProbe = "testingt-sync-observer-probe"
+
+// RequireSyncObserver pins down the OnChange contract: it fatals unless an
+// observer has already been called by the time Add and Rm return. The probe
+// key it uses must not already be in s, s is left as it was found.
+//
+// This takes a testing.TB rather than a *testing.T, so its failures can be
+// asserted with a FakeTB.
+func RequireSyncObserver(tb testing.TB, s *Store) {
+	tb.Helper()
+
+	if s.Has(syncObserverProbe) {
+		tb.Fatalf("store already has probe key %q", syncObserverProbe)
+	}
+
+	var called bool
+	unregister := s.OnChange(func(Change) { called = true })
+	defer unregister()
+
+	if err := s.Add(syncObserverProbe); err != nil {
+		tb.Fatalf("failed to add probe key: %s", err)
+	}
+	if !called {
+		s.Rm(syncObserverProbe)
+		tb.Fatal("observer was not called before Add returned")
+	}
+
+	called = false
+	s.Rm(syncObserverProbe)
+	if !called {
+		tb.Fatal("observer was not called before Rm returned")
+	}
+}
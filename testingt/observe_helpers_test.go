@@ -0,0 +1,107 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireObservedOrder(t *testing.T) {
+	var s testingt.Store
+	mutate := func() {
+		_ = s.Add("first")
+		_ = s.Add("second")
+		s.Rm("first")
+		_ = s.Add("third")
+	}
+	want := []testingt.Change{
+		{Op: testingt.OpAdd, Key: "first"},
+		{Op: testingt.OpAdd, Key: "second"},
+		{Op: testingt.OpRm, Key: "first"},
+		{Op: testingt.OpAdd, Key: "third"},
+	}
+
+	t.Run("matching sequence", func(t *testing.T) {
+		testingt.RequireObservedOrder(t, &s, mutate, want)
+	})
+
+	t.Run("out of order sequence", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireObservedOrder(tb, &s, func() {
+				_ = s.Add("second")
+				_ = s.Add("first")
+			}, []testingt.Change{
+				{Op: testingt.OpAdd, Key: "first"},
+				{Op: testingt.OpAdd, Key: "second"},
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"unexpected changes observed:\n\tgot:  [{add second} {add first}]\n\twant: [{add first} {add second}]"}, fake.Errors())
+	})
+}
+
+func TestBalanceTracker(t *testing.T) {
+	t.Run("balanced by cleanups", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.NewBalanceTracker(tb, &s)
+			testingt.AddKeysStrict(tb, &s, "first", "second", "third")
+		})
+
+		assert.False(t, fake.Failed(), fake.Errors())
+	})
+
+	t.Run("unbalanced", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			bt := testingt.NewBalanceTracker(tb, &s)
+			_ = s.Add("first")
+			_ = s.Add("second")
+			s.Rm("first")
+			assert.Equal(t, 1, bt.Balance())
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"unbalanced store: 1 more add(s) than removes, want 0"}, fake.Errors())
+	})
+
+	t.Run("expected residual", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			bt := testingt.NewBalanceTracker(tb, &s)
+			bt.Expect(2)
+			_ = s.Add("first")
+			_ = s.Add("second")
+		})
+
+		assert.False(t, fake.Failed(), fake.Errors())
+	})
+}
+
+func TestRequireSyncObserver(t *testing.T) {
+	t.Run("observers are called synchronously", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("existing"))
+
+		testingt.RequireSyncObserver(t, &s)
+
+		assert.Equal(t, []string{"existing"}, s.Keys())
+	})
+
+	t.Run("failed add is reported", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithCapacity(1))
+		require.NoError(t, s.Add("existing"))
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSyncObserver(tb, s)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Contains(t, fake.Errors()[0], "failed to add probe key")
+	})
+}
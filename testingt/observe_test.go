@@ -0,0 +1,117 @@
+package testingt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestStore_OnChange(t *testing.T) {
+	var (
+		s   testingt.Store
+		got []testingt.Change
+	)
+	unregister := s.OnChange(func(c testingt.Change) { got = append(got, c) })
+
+	require.NoError(t, s.Add("first"))
+	require.NoError(t, s.Add("first"))
+	s.Rm("first")
+	s.Rm("nope")
+
+	unregister()
+	require.NoError(t, s.Add("second"))
+
+	assert.Equal(t, []testingt.Change{
+		{Op: testingt.OpAdd, Key: "first"},
+		{Op: testingt.OpRm, Key: "first"},
+	}, got)
+}
+
+func TestChangeChannel(t *testing.T) {
+	var s testingt.Store
+	ch := testingt.ChangeChannel(&s)
+
+	require.NoError(t, s.Add("first"))
+	require.NoError(t, s.Add("second"))
+	s.Rm("first")
+
+	want := []testingt.Change{
+		{Op: testingt.OpAdd, Key: "first"},
+		{Op: testingt.OpAdd, Key: "second"},
+		{Op: testingt.OpRm, Key: "first"},
+	}
+	for _, w := range want {
+		select {
+		case c := <-ch:
+			assert.Equal(t, w, c)
+		default:
+			require.FailNow(t, "expected change on channel", w)
+		}
+	}
+
+	select {
+	case c := <-ch:
+		assert.Fail(t, "unexpected change", c)
+	default:
+	}
+}
+
+func TestStore_OnChange_ReplacedState(t *testing.T) {
+	newObserved := func(t *testing.T, keys ...string) (*testingt.Store, *[]testingt.Change) {
+		t.Helper()
+
+		var s testingt.Store
+		for _, k := range keys {
+			require.NoError(t, s.Add(k))
+		}
+		var got []testingt.Change
+		s.OnChange(func(c testingt.Change) { got = append(got, c) })
+		return &s, &got
+	}
+
+	t.Run("committed Tx", func(t *testing.T) {
+		s, got := newObserved(t, "first", "second")
+
+		err := testingt.Tx(t, s, func(tx *testingt.Store) error {
+			tx.Rm("first")
+			return tx.Add("third")
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []testingt.Change{
+			{Op: testingt.OpRm, Key: "first"},
+			{Op: testingt.OpAdd, Key: "third"},
+		}, *got)
+	})
+
+	t.Run("discarded Tx", func(t *testing.T) {
+		s, got := newObserved(t, "first")
+
+		err := testingt.Tx(t, s, func(tx *testingt.Store) error {
+			tx.Rm("first")
+			return errors.New("rollback")
+		})
+
+		require.Error(t, err)
+		assert.Empty(t, *got)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		s, got := newObserved(t, "first", "second")
+		snap := s.Snapshot()
+		s.Rm("second")
+		require.NoError(t, s.Add("third"))
+		*got = nil
+
+		s.Restore(snap)
+
+		assert.Equal(t, []testingt.Change{
+			{Op: testingt.OpRm, Key: "third"},
+			{Op: testingt.OpAdd, Key: "second"},
+		}, *got)
+	})
+}
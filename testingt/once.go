@@ -0,0 +1,44 @@
+package testingt
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+var fixtures sync.Map // map[string]*fixture
+
+type fixture struct {
+	once sync.Once
+	v    any
+}
+
+// Once returns the fixture stored under key, running setup to create it the
+// first time the key is requested. Concurrent callers asking for the same
+// key share a single instance, making it a good fit for an expensive
+// fixture shared by parallel subtests.
+//
+// Pass the parent test rather than the subtest: the fixture is torn down
+// (removed, and closed if it implements io.Closer) in a cleanup registered
+// on the test handed to the first call, and the parent's cleanups wait on
+// all of its subtests.
+func Once(tb testing.TB, key string, setup func() any) any {
+	tb.Helper()
+
+	f := new(fixture)
+	if existing, loaded := fixtures.LoadOrStore(key, f); loaded {
+		f = existing.(*fixture)
+	} else {
+		tb.Cleanup(func() {
+			fixtures.Delete(key)
+			if c, ok := f.v.(io.Closer); ok {
+				if err := c.Close(); err != nil {
+					tb.Errorf("failed to close fixture %q: %s", key, err)
+				}
+			}
+		})
+	}
+
+	f.once.Do(func() { f.v = setup() })
+	return f.v
+}
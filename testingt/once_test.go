@@ -0,0 +1,43 @@
+package testingt_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestOnce(t *testing.T) {
+	var (
+		setups atomic.Int32
+		got    [2]*testingt.Store
+	)
+	setup := func() any {
+		setups.Add(1)
+		return new(testingt.Store)
+	}
+
+	t.Run("group", func(t *testing.T) {
+		parent := t
+		for i, name := range []string{"first", "second"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got[i] = testingt.Once(parent, "shared-store", setup).(*testingt.Store)
+			})
+		}
+	})
+
+	assert.Equal(t, int32(1), setups.Load())
+	assert.NotNil(t, got[0])
+	assert.Same(t, got[0], got[1])
+
+	t.Run("torn down with the parent", func(t *testing.T) {
+		fresh := testingt.Once(t, "shared-store", setup).(*testingt.Store)
+
+		assert.NotSame(t, got[0], fresh)
+		assert.Equal(t, int32(2), setups.Load())
+	})
+}
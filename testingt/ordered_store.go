@@ -0,0 +1,104 @@
+package testingt
+
+import (
+	"fmt"
+	"slices"
+)
+
+// OrderedStore is a set of string keys that remembers insertion order. The
+// zero value is ready to use, use NewOrderedStore to configure it.
+type OrderedStore struct {
+	keys []string
+	idx  map[string]int
+
+	sorted bool
+}
+
+var _ KeyStore = (*OrderedStore)(nil)
+
+// OrderedStoreOpt configures an OrderedStore created by NewOrderedStore.
+type OrderedStoreOpt func(*OrderedStore)
+
+// WithSortedInsert keeps the store's keys sorted rather than in insertion
+// order.
+func WithSortedInsert() OrderedStoreOpt {
+	return func(s *OrderedStore) {
+		s.sorted = true
+	}
+}
+
+// NewOrderedStore creates an OrderedStore configured by opts.
+func NewOrderedStore(opts ...OrderedStoreOpt) *OrderedStore {
+	var s OrderedStore
+	for _, o := range opts {
+		o(&s)
+	}
+	return &s
+}
+
+// Add adds the key to the end of the store, or at its sorted position with
+// WithSortedInsert. Re-adding an existing key leaves its position untouched.
+func (s *OrderedStore) Add(k string) error {
+	if s.idx == nil {
+		s.idx = make(map[string]int)
+	}
+	if _, ok := s.idx[k]; ok {
+		return nil
+	}
+
+	i := len(s.keys)
+	if s.sorted {
+		i, _ = slices.BinarySearch(s.keys, k)
+	}
+	s.keys = slices.Insert(s.keys, i, k)
+	s.reindex(i)
+	return nil
+}
+
+// Rm removes the key from the store, preserving the order of the rest.
+func (s *OrderedStore) Rm(k string) {
+	i, ok := s.idx[k]
+	if !ok {
+		return
+	}
+	delete(s.idx, k)
+	s.keys = slices.Delete(s.keys, i, i+1)
+	s.reindex(i)
+}
+
+// reindex updates the index of every key from position i onward.
+func (s *OrderedStore) reindex(i int) {
+	for j := i; j < len(s.keys); j++ {
+		s.idx[s.keys[j]] = j
+	}
+}
+
+// Has reports whether the key is in the store.
+func (s *OrderedStore) Has(k string) bool {
+	_, ok := s.idx[k]
+	return ok
+}
+
+// Len returns the number of keys in the store.
+func (s *OrderedStore) Len() int {
+	return len(s.keys)
+}
+
+// Keys returns the keys in insertion order, or sorted with WithSortedInsert.
+func (s *OrderedStore) Keys() []string {
+	return slices.Clone(s.keys)
+}
+
+// Range calls fn for each key in insertion order, or sorted with
+// WithSortedInsert, stopping early if fn returns false.
+func (s *OrderedStore) Range(fn func(k string) bool) {
+	for _, k := range s.keys {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func (s *OrderedStore) String() string {
+	return fmt.Sprint(s.keys)
+}
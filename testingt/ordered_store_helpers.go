@@ -0,0 +1,32 @@
+package testingt
+
+import (
+	"slices"
+	"testing"
+)
+
+// RequireRangeOrder fatals unless ranging over s yields exactly want, in
+// order.
+func RequireRangeOrder(tb testing.TB, s *OrderedStore, want ...string) {
+	tb.Helper()
+
+	var got []string
+	s.Range(func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slices.Equal(got, want) {
+		tb.Fatalf("unexpected range order:\n\tgot:  %q\n\twant: %q", got, want)
+	}
+}
+
+// RequireSortedInvariant fatals unless s.Keys() is sorted. Call it after
+// every mutation of a store created with WithSortedInsert to check the
+// invariant holds throughout.
+func RequireSortedInvariant(tb testing.TB, s *OrderedStore) {
+	tb.Helper()
+
+	if keys := s.Keys(); !slices.IsSorted(keys) {
+		tb.Fatalf("keys are not sorted: %q", keys)
+	}
+}
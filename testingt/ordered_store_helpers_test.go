@@ -0,0 +1,70 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireRangeOrder(t *testing.T) {
+	t.Run("insertion order", func(t *testing.T) {
+		var s testingt.OrderedStore
+		for _, k := range []string{"c", "a", "b", "a"} {
+			require.NoError(t, s.Add(k))
+		}
+
+		testingt.RequireRangeOrder(t, &s, "c", "a", "b")
+
+		s.Rm("c")
+		require.NoError(t, s.Add("c"))
+		testingt.RequireRangeOrder(t, &s, "a", "b", "c")
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		var s testingt.OrderedStore
+		for _, k := range []string{"c", "a", "b"} {
+			require.NoError(t, s.Add(k))
+		}
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			// a sorted order is what a map backed implementation bug would
+			// most likely hand back
+			testingt.RequireRangeOrder(tb, &s, "a", "b", "c")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"unexpected range order:\n\tgot:  [\"c\" \"a\" \"b\"]\n\twant: [\"a\" \"b\" \"c\"]"}, fake.Errors())
+	})
+}
+
+func TestRequireSortedInvariant(t *testing.T) {
+	t.Run("sorted insert stays sorted", func(t *testing.T) {
+		s := testingt.NewOrderedStore(testingt.WithSortedInsert())
+		for _, k := range []string{"m", "c", "x", "a", "c", "q"} {
+			require.NoError(t, s.Add(k))
+			testingt.RequireSortedInvariant(t, s)
+		}
+		s.Rm("m")
+		testingt.RequireSortedInvariant(t, s)
+
+		assert.Equal(t, []string{"a", "c", "q", "x"}, s.Keys())
+		testingt.RequireRangeOrder(t, s, "a", "c", "q", "x")
+	})
+
+	t.Run("insertion order breaks the invariant", func(t *testing.T) {
+		var s testingt.OrderedStore
+		for _, k := range []string{"b", "a"} {
+			require.NoError(t, s.Add(k))
+		}
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSortedInvariant(tb, &s)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`keys are not sorted: ["b" "a"]`}, fake.Errors())
+	})
+}
@@ -0,0 +1,26 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestOrderedStore_Range(t *testing.T) {
+	t.Run("stops when fn returns false", func(t *testing.T) {
+		var s testingt.OrderedStore
+		for _, k := range []string{"c", "a", "b"} {
+			require.NoError(t, s.Add(k))
+		}
+
+		var got []string
+		s.Range(func(k string) bool {
+			got = append(got, k)
+			return len(got) < 2
+		})
+		assert.Equal(t, []string{"c", "a"}, got)
+	})
+}
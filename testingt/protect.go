@@ -0,0 +1,32 @@
+package testingt
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+// Protect runs body, turning any panic into a fatal test failure that
+// includes the stack, rather than letting it crash the test binary. Handy
+// when body calls into code that may panic on bad input.
+func Protect(tb testing.TB, body func()) {
+	tb.Helper()
+
+	var (
+		recovered any
+		stack     []byte
+		panicked  = true
+	)
+	func() {
+		defer func() {
+			if panicked {
+				recovered, stack = recover(), debug.Stack()
+			}
+		}()
+		body()
+		panicked = false
+	}()
+
+	if panicked {
+		tb.Fatalf("panic: %v\n%s", recovered, stack)
+	}
+}
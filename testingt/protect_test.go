@@ -0,0 +1,36 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestProtect(t *testing.T) {
+	t.Run("panicking body is a fatal", func(t *testing.T) {
+		var reached bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.Protect(tb, func() {
+				var m map[string]int
+				m["boom"] = 1
+			})
+			reached = true
+		})
+
+		require.True(t, fake.Failed())
+		require.Len(t, fake.Errors(), 1)
+		assert.Contains(t, fake.Errors()[0], "panic: assignment to entry in nil map")
+		assert.Contains(t, fake.Errors()[0], "goroutine")
+		assert.False(t, reached, "Protect should halt the test")
+	})
+
+	t.Run("well behaved body", func(t *testing.T) {
+		var ran bool
+		testingt.Protect(t, func() { ran = true })
+
+		assert.True(t, ran)
+	})
+}
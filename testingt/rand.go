@@ -0,0 +1,57 @@
+package testingt
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Rand returns a *rand.Rand seeded from the test's name and logs the seed.
+// The same test gets the same sequence every run, so a test leaning on
+// randomness stays reproducible while different tests still see different
+// sequences.
+func Rand(tb testing.TB) *rand.Rand {
+	tb.Helper()
+
+	h := fnv.New64a()
+	h.Write([]byte(tb.Name()))
+	seed := int64(h.Sum64())
+
+	logf(tb, "rand seed for %s: %d", tb.Name(), seed)
+	return rand.New(rand.NewSource(seed))
+}
+
+// ShuffledKeys returns a shuffled copy of keys, shuffled with the test's
+// Rand so the order is reproducible for a given test. Handy for adding keys
+// in a random, yet repeatable, order.
+func ShuffledKeys(tb testing.TB, keys []string) []string {
+	tb.Helper()
+
+	shuffled := slices.Clone(keys)
+	Rand(tb).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// fixtureKeyAlphabet is the alphabet FixtureStore draws its keys from.
+const fixtureKeyAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// FixtureStore returns a Store holding n random keys generated from seed.
+// The same seed and n always produce the same store, making for
+// reproducible fixtures that are still free of hand picked keys.
+func FixtureStore(seed int64, n int) *Store {
+	r := rand.New(rand.NewSource(seed))
+
+	s := NewStore()
+	key := make([]byte, 8)
+	for s.Len() < n {
+		for i := range key {
+			key[i] = fixtureKeyAlphabet[r.Intn(len(fixtureKeyAlphabet))]
+		}
+		// a plain Store can't fail an Add
+		_ = s.Add(string(key))
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package testingt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRand(t *testing.T) {
+	var seqs [2][]int
+	for i := range seqs {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			r := testingt.Rand(tb)
+			for range 5 {
+				seqs[i] = append(seqs[i], r.Int())
+			}
+		})
+		assert.Len(t, fake.Logs(), 1, "seed should be logged")
+	}
+
+	assert.Equal(t, seqs[0], seqs[1], "same test name should yield the same sequence")
+}
+
+func TestShuffledKeys(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	var shuffles [2][]string
+	for i := range shuffles {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			shuffles[i] = testingt.ShuffledKeys(tb, keys)
+		})
+		if assert.Len(t, fake.Logs(), 1, "seed should be logged") {
+			assert.Contains(t, fake.Logs()[0], "rand seed for "+t.Name()+": ")
+		}
+	}
+
+	assert.Equal(t, shuffles[0], shuffles[1], "same test name should yield the same shuffle")
+	assert.ElementsMatch(t, keys, shuffles[0])
+	assert.NotEqual(t, keys, shuffles[0], "keys should be shuffled")
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h"}, keys, "input should be left untouched")
+
+	var s testingt.Store
+	for _, k := range shuffles[0] {
+		assert.NoError(t, s.Add(k))
+	}
+	assert.Equal(t, keys, s.Keys())
+}
+
+func TestFixtureStore(t *testing.T) {
+	t.Run("same seed yields the same store", func(t *testing.T) {
+		a, b := testingt.FixtureStore(42, 50), testingt.FixtureStore(42, 50)
+
+		assert.Equal(t, 50, a.Len())
+		assert.Equal(t, a.Keys(), b.Keys())
+	})
+
+	t.Run("different seeds yield different stores", func(t *testing.T) {
+		a, b := testingt.FixtureStore(1, 50), testingt.FixtureStore(2, 50)
+
+		assert.NotEqual(t, a.Keys(), b.Keys())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Zero(t, testingt.FixtureStore(1, 0).Len())
+	})
+}
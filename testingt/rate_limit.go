@@ -0,0 +1,39 @@
+package testingt
+
+import "time"
+
+// tokenBucket is a token bucket rate limiter. It starts full, holding up to
+// burst tokens, and refills at rate tokens per second.
+type tokenBucket struct {
+	clock  Clock
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(clock Clock, rps int) *tokenBucket {
+	return &tokenBucket{
+		clock:  clock,
+		rate:   float64(rps),
+		burst:  float64(rps),
+		tokens: float64(rps),
+		last:   clock.Now(),
+	}
+}
+
+// take takes a token from the bucket, sleeping until one is available.
+func (b *tokenBucket) take() {
+	now := b.clock.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return
+	}
+
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	b.clock.Sleep(wait)
+	b.tokens = 0
+	b.last = b.clock.Now()
+}
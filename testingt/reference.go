@@ -0,0 +1,98 @@
+package testingt
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// RequireMatchesReference applies ops to both impl and a trivial map backed
+// reference, then fatals unless both end up holding the same keys. Feed it
+// generated ops for a model based test of a KeyStore implementation.
+func RequireMatchesReference(tb testing.TB, ops []Change, impl KeyStore) {
+	tb.Helper()
+
+	ref := make(map[string]struct{})
+	for _, op := range ops {
+		switch op.Op {
+		case OpAdd:
+			ref[op.Key] = struct{}{}
+		case OpRm:
+			delete(ref, op.Key)
+		}
+	}
+	applyOps(tb, impl, ops)
+
+	want := slices.Sorted(maps.Keys(ref))
+	got := slices.Sorted(slices.Values(impl.Keys()))
+	if !slices.Equal(got, want) {
+		tb.Fatalf("implementation diverged from reference after %d ops:\n\tgot:  %q\n\twant: %q", len(ops), got, want)
+	}
+}
+
+func applyOps(tb testing.TB, s KeyStore, ops []Change) {
+	tb.Helper()
+
+	for i, op := range ops {
+		switch op.Op {
+		case OpAdd:
+			if err := s.Add(op.Key); err != nil {
+				tb.Fatalf("op %d: failed to add key %q: %s", i, op.Key, err)
+			}
+		case OpRm:
+			s.Rm(op.Key)
+		default:
+			tb.Fatalf("op %d: unknown op %q", i, op.Op)
+		}
+	}
+}
+
+// ConvergeOpt configures RequireConverges.
+type ConvergeOpt func(*convergeConfig)
+
+type convergeConfig struct {
+	newStore func() KeyStore
+}
+
+// WithConvergeStore has RequireConverges apply the ops to stores made by
+// newStore, rather than to a pair of Stores.
+func WithConvergeStore(newStore func() KeyStore) ConvergeOpt {
+	return func(c *convergeConfig) {
+		c.newStore = newStore
+	}
+}
+
+// RequireConverges applies ops to one store and a shuffled permutation of
+// ops, shuffled with the test's Rand, to another, then fatals unless both
+// end up with the same Keys. Set adds and removes of distinct keys
+// commute, so ops mixing an add and a remove of the same key are rejected
+// up front since their order matters.
+func RequireConverges(tb testing.TB, ops []Change, opts ...ConvergeOpt) {
+	tb.Helper()
+
+	cfg := convergeConfig{newStore: func() KeyStore { return new(Store) }}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	kinds := make(map[string]Op)
+	for _, op := range ops {
+		if kind, ok := kinds[op.Key]; ok && kind != op.Op {
+			tb.Fatalf("ops for key %q mix %s and %s, they don't commute", op.Key, kind, op.Op)
+		}
+		kinds[op.Key] = op.Op
+	}
+
+	shuffled := slices.Clone(ops)
+	Rand(tb).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	inOrder, reordered := cfg.newStore(), cfg.newStore()
+	applyOps(tb, inOrder, ops)
+	applyOps(tb, reordered, shuffled)
+
+	if got, want := reordered.Keys(), inOrder.Keys(); !slices.Equal(got, want) {
+		tb.Fatalf("stores diverged after shuffling ops:\n\tshuffled: %q\n\tin order: %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package testingt_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireMatchesReference(t *testing.T) {
+	ops := generateOps(rand.New(rand.NewSource(42)), 500)
+
+	impls := map[string]func() testingt.KeyStore{
+		"Store":        func() testingt.KeyStore { return new(testingt.Store) },
+		"SyncStore":    func() testingt.KeyStore { return new(testingt.SyncStore) },
+		"OrderedStore": func() testingt.KeyStore { return new(testingt.OrderedStore) },
+		"sorted OrderedStore": func() testingt.KeyStore {
+			return testingt.NewOrderedStore(testingt.WithSortedInsert())
+		},
+	}
+	for name, newImpl := range impls {
+		t.Run(name, func(t *testing.T) {
+			testingt.RequireMatchesReference(t, ops, newImpl())
+		})
+	}
+
+	t.Run("diverging implementation", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireMatchesReference(tb, []testingt.Change{
+				{Op: testingt.OpAdd, Key: "first"},
+				{Op: testingt.OpAdd, Key: "second"},
+				{Op: testingt.OpRm, Key: "first"},
+			}, new(forgetfulStore))
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"implementation diverged from reference after 3 ops:\n\tgot:  [\"first\" \"second\"]\n\twant: [\"second\"]"}, fake.Errors())
+	})
+
+	t.Run("failed add", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithCapacity(1))
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireMatchesReference(tb, []testingt.Change{
+				{Op: testingt.OpAdd, Key: "first"},
+				{Op: testingt.OpAdd, Key: "second"},
+			}, s)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`op 1: failed to add key "second": adding key "second": store capacity exceeded`}, fake.Errors())
+	})
+}
+
+// forgetfulStore is a buggy KeyStore that never removes anything.
+type forgetfulStore struct {
+	testingt.Store
+}
+
+func (*forgetfulStore) Rm(string) {}
+
+// generateOps generates n adds and removes over a small key space, so that
+// plenty of the removes hit keys that exist.
+func generateOps(r *rand.Rand, n int) []testingt.Change {
+	ops := make([]testingt.Change, n)
+	for i := range ops {
+		op := testingt.OpAdd
+		if r.Intn(3) == 0 {
+			op = testingt.OpRm
+		}
+		ops[i] = testingt.Change{Op: op, Key: "key-" + strconv.Itoa(r.Intn(50))}
+	}
+	return ops
+}
+
+func TestRequireConverges(t *testing.T) {
+	t.Run("commuting ops", func(t *testing.T) {
+		ops := []testingt.Change{
+			{Op: testingt.OpAdd, Key: "a"},
+			{Op: testingt.OpAdd, Key: "b"},
+			{Op: testingt.OpRm, Key: "c"},
+			{Op: testingt.OpAdd, Key: "d"},
+			{Op: testingt.OpAdd, Key: "a"},
+			{Op: testingt.OpRm, Key: "e"},
+			{Op: testingt.OpAdd, Key: "f"},
+		}
+
+		testingt.RequireConverges(t, ops)
+	})
+
+	t.Run("order sensitive store", func(t *testing.T) {
+		// an OrderedStore's Keys are in insertion order, so it only
+		// converges if the ops weren't reordered
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireConverges(tb, []testingt.Change{
+				{Op: testingt.OpAdd, Key: "a"},
+				{Op: testingt.OpAdd, Key: "b"},
+				{Op: testingt.OpAdd, Key: "c"},
+				{Op: testingt.OpAdd, Key: "d"},
+				{Op: testingt.OpAdd, Key: "e"},
+				{Op: testingt.OpAdd, Key: "f"},
+			}, testingt.WithConvergeStore(func() testingt.KeyStore {
+				return testingt.NewOrderedStore()
+			}))
+		})
+
+		assert.True(t, fake.Failed())
+		if assert.Len(t, fake.Errors(), 1) {
+			assert.Contains(t, fake.Errors()[0], "stores diverged after shuffling ops")
+			assert.Contains(t, fake.Errors()[0], `in order: ["a" "b" "c" "d" "e" "f"]`)
+		}
+	})
+
+	t.Run("non commuting ops", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireConverges(tb, []testingt.Change{
+				{Op: testingt.OpAdd, Key: "a"},
+				{Op: testingt.OpRm, Key: "a"},
+			})
+		})
+
+		assert.True(t, fake.Failed())
+		assert.Equal(t, []string{`ops for key "a" mix add and rm, they don't commute`}, fake.Errors())
+	})
+}
@@ -0,0 +1,67 @@
+package testingt
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ScaleOpt configures ScaleTest.
+type ScaleOpt func(*scaleConfig)
+
+type scaleConfig struct {
+	maxGrowth float64
+}
+
+// WithMaxGrowth fails a ScaleTest whose empirical growth exponent, between
+// its smallest and largest sizes, exceeds exp. An exponent of 1 is linear
+// and 2 is quadratic, so 1.5 is a reasonable "sub quadratic" bound that
+// leaves room for noise.
+func WithMaxGrowth(exp float64) ScaleOpt {
+	return func(c *scaleConfig) {
+		c.maxGrowth = exp
+	}
+}
+
+// ScaleTest runs body once per size, smallest size first, and logs a table
+// of how long each size took, as reported by body. Handy for sanity
+// checking the big-O of an operation, e.g. seeding a store. The sizes are
+// sorted on a copy, so the caller's slice is left as is and growth is
+// always measured from the smallest size to the largest.
+func ScaleTest(t *testing.T, sizes []int, body func(t *testing.T, n int) time.Duration, opts ...ScaleOpt) {
+	t.Helper()
+
+	var cfg scaleConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	sizes = slices.Sorted(slices.Values(sizes))
+	ds := make([]time.Duration, len(sizes))
+	var table strings.Builder
+	fmt.Fprintf(&table, "%12s  %14s  %12s", "size", "duration", "per op")
+	for i, n := range sizes {
+		ds[i] = body(t, n)
+		fmt.Fprintf(&table, "\n%12d  %14s  %12s", n, ds[i], ds[i]/time.Duration(max(n, 1)))
+	}
+	logf(t, "scaling:\n%s", table.String())
+
+	if cfg.maxGrowth == 0 || len(sizes) < 2 || sizes[0] == sizes[len(sizes)-1] {
+		return
+	}
+
+	first, last := 0, len(sizes)-1
+	if sizes[first] <= 0 {
+		t.Fatalf("sizes must be positive to measure growth, got %d", sizes[first])
+	}
+	if ds[first] <= 0 || ds[last] <= 0 {
+		t.Fatalf("durations must be positive to measure growth, got %s and %s", ds[first], ds[last])
+	}
+	growth := math.Log(float64(ds[last])/float64(ds[first])) / math.Log(float64(sizes[last])/float64(sizes[first]))
+	if growth > cfg.maxGrowth {
+		t.Fatalf("growth exponent %.2f from n=%d to n=%d exceeds the max of %.2f", growth, sizes[first], sizes[last], cfg.maxGrowth)
+	}
+}
@@ -0,0 +1,68 @@
+package testingt_test
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestScaleTest(t *testing.T) {
+	t.Run("seeding a store scales roughly linearly", func(t *testing.T) {
+		seed := func(t *testing.T, n int) time.Duration {
+			keys := make([]string, n)
+			for i := range keys {
+				keys[i] = "key-" + strconv.Itoa(i)
+			}
+
+			var s testingt.Store
+			start := time.Now()
+			for _, k := range keys {
+				require.NoError(t, s.Add(k))
+			}
+			return time.Since(start)
+		}
+
+		testingt.ScaleTest(t, []int{10_000, 40_000, 160_000}, seed, testingt.WithMaxGrowth(1.5))
+	})
+
+	t.Run("sizes run smallest first", func(t *testing.T) {
+		sizes := []int{1000, 10, 100}
+		var ran []int
+		linear := func(t *testing.T, n int) time.Duration {
+			ran = append(ran, n)
+			return time.Duration(n) * time.Microsecond
+		}
+
+		testingt.ScaleTest(t, sizes, linear, testingt.WithMaxGrowth(1.5))
+
+		assert.Equal(t, []int{10, 100, 1000}, ran)
+		assert.Equal(t, []int{1000, 10, 100}, sizes, "caller's sizes should be left as is")
+	})
+}
+
+func TestScaleTest_Quadratic(t *testing.T) {
+	// ScaleTest fails the *testing.T it's given, so the failing run happens
+	// in a child test process and the parent asserts on its output.
+	if os.Getenv("TESTINGT_QUADRATIC_SCALE") == "1" {
+		quadratic := func(t *testing.T, n int) time.Duration {
+			return time.Duration(n*n) * time.Nanosecond
+		}
+		testingt.ScaleTest(t, []int{1000, 10, 100}, quadratic, testingt.WithMaxGrowth(1.5))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestScaleTest_Quadratic$", "-test.v")
+	cmd.Env = append(os.Environ(), "TESTINGT_QUADRATIC_SCALE=1")
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr, "child test should fail:\n%s", out)
+	assert.Contains(t, string(out), "growth exponent 2.00 from n=10 to n=1000 exceeds the max of 1.50")
+}
@@ -0,0 +1,96 @@
+package testingt
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by StoreService.Remove for a key that isn't in
+// the store.
+var ErrKeyNotFound = errors.New("key not found")
+
+// StoreService is the RPC style interface to a store, the shape a gRPC or
+// other remote backend would have. See NewStoreService for the in-process
+// implementation, and DirectClient for calling one without a network.
+type StoreService interface {
+	Add(ctx context.Context, key string) error
+	Remove(ctx context.Context, key string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// NewStoreService returns a StoreService backed by s. Calls are serialized,
+// since a Store isn't safe for concurrent use, don't mutate s directly while
+// the service is in use.
+func NewStoreService(s *Store) StoreService {
+	return &storeService{store: s}
+}
+
+type storeService struct {
+	mu    sync.Mutex
+	store *Store
+}
+
+func (s *storeService) Add(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.Add(key)
+}
+
+func (s *storeService) Remove(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.store.Has(key) {
+		return ErrKeyNotFound
+	}
+	s.store.Rm(key)
+	return nil
+}
+
+func (s *storeService) List(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.Keys(), nil
+}
+
+// DirectClient calls a StoreService in-process, synchronously, standing in
+// for a network client so client code can be tested against a real backend.
+// It keeps the parts of a transport a client can observe: a call on a done
+// context fails with the context's error without reaching the service, and
+// results are copied so the client never shares memory with the backend.
+type DirectClient struct {
+	svc StoreService
+}
+
+var _ StoreService = (*DirectClient)(nil)
+
+// NewDirectClient creates a DirectClient calling svc.
+func NewDirectClient(svc StoreService) *DirectClient {
+	return &DirectClient{svc: svc}
+}
+
+// Add adds key via the service.
+func (c *DirectClient) Add(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.svc.Add(ctx, key)
+}
+
+// Remove removes key via the service, ErrKeyNotFound if it's absent.
+func (c *DirectClient) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.svc.Remove(ctx, key)
+}
+
+// List returns the keys via the service.
+func (c *DirectClient) List(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	keys, err := c.svc.List(ctx)
+	return slices.Clone(keys), err
+}
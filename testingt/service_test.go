@@ -0,0 +1,59 @@
+package testingt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestDirectClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round trips against the store", func(t *testing.T) {
+		var s testingt.Store
+		client := testingt.NewDirectClient(testingt.NewStoreService(&s))
+
+		require.NoError(t, client.Add(ctx, "second"))
+		require.NoError(t, client.Add(ctx, "first"))
+		require.NoError(t, client.Remove(ctx, "second"))
+
+		keys, err := client.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first"}, keys)
+		assert.Equal(t, []string{"first"}, s.Keys())
+	})
+
+	t.Run("removing a missing key", func(t *testing.T) {
+		var s testingt.Store
+		client := testingt.NewDirectClient(testingt.NewStoreService(&s))
+
+		err := client.Remove(ctx, "missing")
+
+		assert.ErrorIs(t, err, testingt.ErrKeyNotFound)
+	})
+
+	t.Run("backend errors are returned", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithStrictKeys())
+		client := testingt.NewDirectClient(testingt.NewStoreService(s))
+
+		err := client.Add(ctx, "")
+
+		assert.ErrorIs(t, err, testingt.ErrEmptyKey)
+	})
+
+	t.Run("canceled context never reaches the backend", func(t *testing.T) {
+		var s testingt.Store
+		client := testingt.NewDirectClient(testingt.NewStoreService(&s))
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.ErrorIs(t, client.Add(ctx, "first"), context.Canceled)
+		_, err := client.List(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, s.Len())
+	})
+}
@@ -0,0 +1,281 @@
+package testingt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// KeyStore is the behavior shared by all the store variants. Helpers that
+// don't care which variant they're handed should accept a KeyStore.
+type KeyStore interface {
+	Add(k string) error
+	Rm(k string)
+	Has(k string) bool
+	Len() int
+	Keys() []string
+}
+
+// ErrCapacityExceeded is returned when adding a new key to a store that is
+// already at its capacity.
+var ErrCapacityExceeded = errors.New("store capacity exceeded")
+
+// ErrEmptyKey is returned when adding an empty key to a store created with
+// WithStrictKeys.
+var ErrEmptyKey = errors.New("empty key")
+
+// Store is a set of string keys. The zero value is ready to use and
+// unbounded, use NewStore to configure it.
+//
+// Store is not safe for concurrent use, reach for SyncStore when
+// multiple goroutines are involved.
+type Store struct {
+	state map[string]bool
+
+	capacity int
+	strict   bool
+	norm     func(string) string
+	rps      int
+	clock    Clock
+	limiter  *tokenBucket
+
+	observers  []observer
+	observerID int
+}
+
+var _ KeyStore = (*Store)(nil)
+
+// StoreOpt configures a Store created by NewStore.
+type StoreOpt func(*Store)
+
+// WithCapacity bounds the store to n keys. Adding a new key to a full store
+// fails with ErrCapacityExceeded. A capacity of 0 is unbounded.
+func WithCapacity(n int) StoreOpt {
+	return func(s *Store) {
+		s.capacity = n
+	}
+}
+
+// WithStrictKeys rejects empty keys, adding one fails with ErrEmptyKey and
+// leaves the store untouched.
+func WithStrictKeys() StoreOpt {
+	return func(s *Store) {
+		s.strict = true
+	}
+}
+
+// WithKeyNormalizer normalizes every key handed to Add, Rm, and Has with fn
+// before it touches the store, e.g. strings.TrimSpace.
+func WithKeyNormalizer(fn func(string) string) StoreOpt {
+	return func(s *Store) {
+		s.norm = fn
+	}
+}
+
+// WithRateLimit throttles the store to rps mutations per second, allowing
+// bursts of up to rps. Add and Rm block once the rate is exceeded. Pair it
+// with WithClock to drive the limiter deterministically in tests.
+func WithRateLimit(rps int) StoreOpt {
+	return func(s *Store) {
+		s.rps = rps
+	}
+}
+
+// WithClock sets the clock the store tells time with, defaults to the
+// system clock.
+func WithClock(c Clock) StoreOpt {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// NewStore creates a Store configured by opts.
+func NewStore(opts ...StoreOpt) *Store {
+	s := Store{clock: realClock{}}
+	for _, o := range opts {
+		o(&s)
+	}
+	if s.rps > 0 {
+		s.limiter = newTokenBucket(s.clock, s.rps)
+	}
+	return &s
+}
+
+// Add adds the key to the store. Re-adding an existing key always succeeds.
+func (s *Store) Add(k string) error {
+	s.throttle()
+	k = s.normalize(k)
+	if s.strict && k == "" {
+		return ErrEmptyKey
+	}
+	if s.state[k] {
+		return nil
+	}
+	if s.capacity > 0 && len(s.state) >= s.capacity {
+		return fmt.Errorf("adding key %q: %w", k, ErrCapacityExceeded)
+	}
+
+	if s.state == nil {
+		s.state = make(map[string]bool)
+	}
+	s.state[k] = true
+	s.notify(Change{Op: OpAdd, Key: k})
+	return nil
+}
+
+// Rm removes the key from the store. Removing an absent key is a no-op.
+func (s *Store) Rm(k string) {
+	s.throttle()
+	k = s.normalize(k)
+	if !s.state[k] {
+		return
+	}
+	delete(s.state, k)
+	s.notify(Change{Op: OpRm, Key: k})
+}
+
+func (s *Store) normalize(k string) string {
+	if s.norm == nil {
+		return k
+	}
+	return s.norm(k)
+}
+
+func (s *Store) throttle() {
+	if s.limiter != nil {
+		s.limiter.take()
+	}
+}
+
+// Has reports whether the key is in the store.
+func (s *Store) Has(k string) bool {
+	return s.state[s.normalize(k)]
+}
+
+// Len returns the number of keys in the store.
+func (s *Store) Len() int {
+	return len(s.state)
+}
+
+// Keys returns the keys in sorted order.
+func (s *Store) Keys() []string {
+	return slices.Sorted(maps.Keys(s.state))
+}
+
+// Format returns the sorted keys joined by sep, e.g. "a, b, c" for a sep
+// of ", ". An empty store formats as "".
+func (s *Store) Format(sep string) string {
+	return strings.Join(s.Keys(), sep)
+}
+
+// DOT returns a Graphviz representation of the store, a root node with an
+// edge to a node per key. Failing tests can write it out as a visual
+// artifact, render it with e.g. `dot -Tsvg`.
+func (s *Store) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph store {\n")
+	b.WriteString("\tstore [shape=box];\n")
+	for _, k := range s.Keys() {
+		q := strconv.Quote(k)
+		b.WriteString("\t" + q + ";\n")
+		b.WriteString("\tstore -> " + q + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// IsSubsetOf reports whether every key in s is also in other.
+func (s *Store) IsSubsetOf(other *Store) bool {
+	for k := range s.state {
+		if !other.Has(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchGlob returns the sorted keys matching the filepath.Match glob
+// pattern, e.g. "user:*". A malformed pattern matches nothing.
+func (s *Store) MatchGlob(pattern string) []string {
+	var matched []string
+	for _, k := range s.Keys() {
+		if ok, _ := filepath.Match(pattern, k); ok {
+			matched = append(matched, k)
+		}
+	}
+	return matched
+}
+
+// Compact reallocates the backing map to fit the current keys. Go maps never
+// shrink, so a store that churned through many keys keeps the memory of its
+// peak size until it's compacted.
+func (s *Store) Compact() {
+	if s.state == nil {
+		return
+	}
+	// maps.Clone keeps the source's capacity, so copy by hand
+	m := make(map[string]bool, len(s.state))
+	for k, v := range s.state {
+		m[k] = v
+	}
+	s.state = m
+}
+
+// Snapshot returns an immutable copy of the store's current state. Later
+// changes to the store are not reflected in the snapshot.
+func (s *Store) Snapshot() StoreSnapshot {
+	return StoreSnapshot{state: maps.Clone(s.state)}
+}
+
+// Restore sets the store to exactly the state captured in snap. Observers
+// see a change for every key the restore adds or removes.
+func (s *Store) Restore(snap StoreSnapshot) {
+	s.replaceState(maps.Clone(snap.state))
+}
+
+func (s *Store) clone() *Store {
+	c := *s
+	c.state = maps.Clone(s.state)
+	c.observers = nil
+	return &c
+}
+
+func (s *Store) String() string {
+	return fmt.Sprint(s.Keys())
+}
+
+// MarshalJSON encodes the store as a JSON array of its sorted keys, an empty
+// store being [] rather than null.
+func (s *Store) MarshalJSON() ([]byte, error) {
+	keys := s.Keys()
+	if keys == nil {
+		keys = []string{}
+	}
+	return json.Marshal(keys)
+}
+
+// StoreSnapshot is a point-in-time copy of a Store, see Store.Snapshot.
+// The zero value is the snapshot of an empty store.
+type StoreSnapshot struct {
+	state map[string]bool
+}
+
+// Has reports whether the key was in the store.
+func (s StoreSnapshot) Has(k string) bool {
+	return s.state[k]
+}
+
+// Len returns the number of keys in the snapshot.
+func (s StoreSnapshot) Len() int {
+	return len(s.state)
+}
+
+// Keys returns the keys in sorted order.
+func (s StoreSnapshot) Keys() []string {
+	return slices.Sorted(maps.Keys(s.state))
+}
@@ -0,0 +1,476 @@
+package testingt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// Tx applies fn to a staging copy of s, committing the staged state into s
+// only when fn returns nil. When fn errors, the staged changes are discarded
+// and s is left exactly as it was, modeling an atomic batch. The error from
+// fn is returned. Observers of s see the committed changes, one per key
+// added or removed, once fn returns and never for a discarded batch.
+func Tx(tb testing.TB, s *Store, fn func(tx *Store) error) error {
+	tb.Helper()
+
+	staged := s.clone()
+	if err := fn(staged); err != nil {
+		logf(tb, "discarding transaction: %s", err)
+		return err
+	}
+	s.replaceState(staged.state)
+	return nil
+}
+
+// AddKeysStrict adds the keys to s, removing each of them again when the
+// test completes. Unlike a plain add, it fatals before adding anything if a
+// key already exists in s or appears more than once in keys, since a set
+// would otherwise silently dedupe them.
+func AddKeysStrict(tb testing.TB, s *Store, keys ...string) {
+	tb.Helper()
+
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		switch {
+		case seen[k]:
+			tb.Fatalf("duplicate key %q in args", k)
+		case s.Has(k):
+			tb.Fatalf("key %q already exists in store", k)
+		}
+		seen[k] = true
+	}
+
+	for _, k := range keys {
+		if err := s.Add(k); err != nil {
+			tb.Fatalf("failed to add key %q: %s", k, err)
+		}
+		tb.Cleanup(func() { s.Rm(k) })
+	}
+}
+
+// RequireStoreError fatals unless err matches want, as reported by
+// errors.Is. Use it with the store's sentinel errors, e.g.
+// ErrCapacityExceeded.
+func RequireStoreError(tb testing.TB, err, want error) {
+	tb.Helper()
+
+	if !errors.Is(err, want) {
+		tb.Fatalf("got error %v, want %v", err, want)
+	}
+}
+
+// RequireSubset fatals unless every key in sub is also in super, listing the
+// keys missing from super. Useful for "expected at least these keys"
+// assertions.
+func RequireSubset(tb testing.TB, sub, super *Store) {
+	tb.Helper()
+
+	if sub.IsSubsetOf(super) {
+		return
+	}
+
+	var missing []string
+	for _, k := range sub.Keys() {
+		if !super.Has(k) {
+			missing = append(missing, k)
+		}
+	}
+	tb.Fatalf("keys missing from superset: %q", missing)
+}
+
+// RequireBoundedMemory churns 10k keys through s, compacts it, and fatals
+// unless the heap is back to within a small slack of where it started. It
+// guards against the store holding onto the memory of its peak size.
+//
+// Heap stats are process wide, so don't run this alongside parallel tests.
+func RequireBoundedMemory(tb testing.TB, s *Store) {
+	tb.Helper()
+
+	const (
+		churn = 10_000
+		slack = 128 << 10
+	)
+
+	base := heapAlloc()
+	for i := range churn {
+		if err := s.Add("churn-" + strconv.Itoa(i)); err != nil {
+			tb.Fatalf("failed to add churn key: %s", err)
+		}
+	}
+	for i := range churn {
+		s.Rm("churn-" + strconv.Itoa(i))
+	}
+	churned := heapAlloc()
+
+	s.Compact()
+	compacted := heapAlloc()
+	runtime.KeepAlive(s)
+
+	logf(tb, "heap: base=%d churned=%d compacted=%d", base, churned, compacted)
+	if compacted > base+slack {
+		tb.Fatalf("heap grew by %d bytes after compacting, want at most %d", compacted-base, slack)
+	}
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+// RequireAllKeys fatals if any key in s fails pred, listing the offending
+// keys alongside msg. Handy for invariants, e.g. "all keys are lowercase".
+func RequireAllKeys(tb testing.TB, s *Store, pred func(string) bool, msg string) {
+	tb.Helper()
+
+	var bad []string
+	for _, k := range s.Keys() {
+		if !pred(k) {
+			bad = append(bad, k)
+		}
+	}
+	if len(bad) > 0 {
+		tb.Fatalf("%s: offending keys %q", msg, bad)
+	}
+}
+
+// DrainInto adds every key received on ch to s until ch is closed or timeout
+// elapses, whichever comes first, and returns the number of keys received.
+// A timeout isn't a failure, it's logged so it's clear the stream was cut
+// off. Fatals if a key can't be added.
+func DrainInto(tb testing.TB, s *Store, ch <-chan string, timeout time.Duration) int {
+	tb.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var n int
+	for {
+		select {
+		case k, ok := <-ch:
+			if !ok {
+				return n
+			}
+			if err := s.Add(k); err != nil {
+				tb.Fatalf("failed to add key %q: %s", k, err)
+			}
+			n++
+		case <-timer.C:
+			logf(tb, "stopped draining after %s with %d key(s) received, channel still open", timeout, n)
+			return n
+		}
+	}
+}
+
+// RequireRejectsEmptyKey fatals unless adding an empty key to s fails with
+// ErrEmptyKey and leaves s untouched, as it does for stores created with
+// WithStrictKeys.
+func RequireRejectsEmptyKey(tb testing.TB, s *Store) {
+	tb.Helper()
+
+	before := s.Len()
+	if err := s.Add(""); !errors.Is(err, ErrEmptyKey) {
+		tb.Fatalf("adding empty key: got error %v, want %v", err, ErrEmptyKey)
+	}
+	if s.Has("") || s.Len() != before {
+		tb.Fatal("empty key was rejected but the store changed")
+	}
+}
+
+// RequireNoCollisions seeds a fresh Store with n generated, unique keys and
+// fatals unless it ends up holding all n of them, catching any accidental
+// dedup of distinct keys.
+func RequireNoCollisions(tb testing.TB, n int) {
+	tb.Helper()
+
+	var s Store
+	for i := range n {
+		if err := s.Add("key-" + strconv.Itoa(i)); err != nil {
+			tb.Fatalf("failed to add key %d: %s", i, err)
+		}
+	}
+	if got := s.Len(); got != n {
+		tb.Fatalf("store holds %d keys after adding %d unique keys", got, n)
+	}
+}
+
+// RequireStoreEqualsMap fatals unless the keys in s are exactly the keys of
+// want mapped to true, so expected state can be written as a map literal.
+// Keys mapped to false are expected to be absent.
+func RequireStoreEqualsMap(tb testing.TB, s *Store, want map[string]bool) {
+	tb.Helper()
+
+	var wantKeys []string
+	for _, k := range slices.Sorted(maps.Keys(want)) {
+		if want[k] {
+			wantKeys = append(wantKeys, k)
+		}
+	}
+	if got := s.Keys(); !slices.Equal(got, wantKeys) {
+		tb.Fatalf("store does not match map:\n\tgot:  %q\n\twant: %q", got, wantKeys)
+	}
+}
+
+// RequireNormalized adds raw to s and fatals unless the key that ends up in
+// the store is want, as it should be for a store created with
+// WithKeyNormalizer. want must not already be in s. The added key is
+// removed again in cleanup.
+func RequireNormalized(tb testing.TB, s *Store, raw, want string) {
+	tb.Helper()
+
+	if s.Has(want) {
+		tb.Fatalf("want key %q is already in the store", want)
+	}
+
+	before := s.Snapshot()
+	if err := s.Add(raw); err != nil {
+		tb.Fatalf("failed to add key %q: %s", raw, err)
+	}
+
+	var added []string
+	for _, k := range s.Keys() {
+		if !before.Has(k) {
+			added = append(added, k)
+		}
+	}
+	tb.Cleanup(func() {
+		for _, k := range added {
+			s.Rm(k)
+		}
+	})
+
+	if !slices.Equal(added, []string{want}) {
+		tb.Fatalf("adding %q stored %q, want %q", raw, added, want)
+	}
+}
+
+// RequireRmSafe removes key, which must be absent from s, and fatals if that
+// panics or changes the store's Len. Removing from a zero value Store, with
+// its nil map, is the case to watch for.
+func RequireRmSafe(tb testing.TB, s *Store, key string) {
+	tb.Helper()
+
+	if s.Has(key) {
+		tb.Fatalf("key %q must be absent from the store", key)
+	}
+
+	before := s.Len()
+	Protect(tb, func() { s.Rm(key) })
+	if after := s.Len(); after != before {
+		tb.Fatalf("removing absent key %q changed Len from %d to %d", key, before, after)
+	}
+}
+
+// RequireValidUTF8 fatals if any key in s, or the store's String output, is
+// not valid UTF-8. Guards against serialization changes that split or
+// mangle multi-byte keys.
+func RequireValidUTF8(tb testing.TB, s *Store) {
+	tb.Helper()
+
+	for _, k := range s.Keys() {
+		if !utf8.ValidString(k) {
+			tb.Errorf("key %q is not valid UTF-8", k)
+		}
+	}
+	if out := s.String(); !utf8.ValidString(out) {
+		tb.Errorf("String output %q is not valid UTF-8", out)
+	}
+	if tb.Failed() {
+		tb.FailNow()
+	}
+}
+
+// snapshotProbe is the key RequireSnapshotImmutable adds after snapshotting.
+const snapshotProbe = "testingt-snapshot-probe"
+
+// RequireSnapshotImmutable snapshots s, then mutates s by adding a probe key
+// and removing every existing key, and fatals if any of that shows up in
+// the snapshot, proving the snapshot copies the store's state rather than
+// aliasing it. The probe key must not already be in s, s is put back the
+// way it was found.
+func RequireSnapshotImmutable(tb testing.TB, s *Store) {
+	tb.Helper()
+
+	if s.Has(snapshotProbe) {
+		tb.Fatalf("store already has probe key %q", snapshotProbe)
+	}
+
+	snap := s.Snapshot()
+	want := s.Keys()
+
+	if err := s.Add(snapshotProbe); err != nil {
+		tb.Fatalf("failed to add probe key: %s", err)
+	}
+	for _, k := range want {
+		s.Rm(k)
+	}
+	defer func() {
+		s.Rm(snapshotProbe)
+		for _, k := range want {
+			if err := s.Add(k); err != nil {
+				tb.Errorf("failed to restore key %q: %s", k, err)
+			}
+		}
+	}()
+
+	if got := snap.Keys(); !slices.Equal(got, want) {
+		tb.Fatalf("snapshot changed along with the store:\n\tgot:  %q\n\twant: %q", got, want)
+	}
+	if snap.Has(snapshotProbe) {
+		tb.Fatalf("snapshot has key %q added after it was taken", snapshotProbe)
+	}
+}
+
+// RequireNearlyEqualSets fatals if want and got differ by more than maxDiff
+// keys, counting keys missing from got and extra keys in got alike. For
+// fuzzy fixtures where a few keys either way are fine.
+func RequireNearlyEqualSets(tb testing.TB, want, got *Store, maxDiff int) {
+	tb.Helper()
+
+	var missing, extra []string
+	for _, k := range want.Keys() {
+		if !got.Has(k) {
+			missing = append(missing, k)
+		}
+	}
+	for _, k := range got.Keys() {
+		if !want.Has(k) {
+			extra = append(extra, k)
+		}
+	}
+
+	if diff := len(missing) + len(extra); diff > maxDiff {
+		tb.Fatalf("sets differ by %d key(s), want at most %d:\n\tmissing: %q\n\textra:   %q", diff, maxDiff, missing, extra)
+	}
+}
+
+// RequireStableMemoryOnDup adds key to s times times and fatals unless the
+// store grew by at most the one key, and re-adding the key, once present,
+// allocated nothing. A set shouldn't pay for duplicates.
+func RequireStableMemoryOnDup(tb testing.TB, s *Store, key string, times int) {
+	tb.Helper()
+
+	before := s.Len()
+	if err := s.Add(key); err != nil {
+		tb.Fatalf("failed to add key %q: %s", key, err)
+	}
+
+	var (
+		addErr error
+		allocs float64
+	)
+	if times > 1 {
+		allocs = testing.AllocsPerRun(times-1, func() {
+			if err := s.Add(key); err != nil && addErr == nil {
+				addErr = err
+			}
+		})
+	}
+	if addErr != nil {
+		tb.Fatalf("failed to re-add key %q: %s", key, addErr)
+	}
+
+	if grew := s.Len() - before; grew > 1 {
+		tb.Errorf("adding %q %d times grew the store by %d keys, want at most 1", key, times, grew)
+	}
+	if allocs > 0 {
+		tb.Errorf("re-adding %q allocated %.0f times per add, want 0", key, allocs)
+	}
+	if tb.Failed() {
+		tb.FailNow()
+	}
+}
+
+// RequireImplements fatals unless *Store still satisfies fmt.Stringer,
+// json.Marshaler, and KeyStore, naming each interface a refactor broke.
+// The package's own compile time checks cover KeyStore, this turns the rest
+// into a test failure with a clear message rather than a surprise in a
+// caller's %v or json.Marshal.
+func RequireImplements(tb testing.TB) {
+	tb.Helper()
+	requireImplements(tb, "*Store", new(Store))
+}
+
+func requireImplements(tb testing.TB, name string, v any) {
+	tb.Helper()
+
+	if _, ok := v.(fmt.Stringer); !ok {
+		tb.Errorf("%s does not implement fmt.Stringer", name)
+	}
+	if _, ok := v.(json.Marshaler); !ok {
+		tb.Errorf("%s does not implement json.Marshaler", name)
+	}
+	if _, ok := v.(KeyStore); !ok {
+		tb.Errorf("%s does not implement KeyStore", name)
+	}
+	if tb.Failed() {
+		tb.FailNow()
+	}
+}
+
+// RequireGlobMatch fatals unless the keys of s matching the filepath.Match
+// glob pattern are exactly want, sorted. A malformed pattern is fatal too,
+// rather than silently matching nothing.
+func RequireGlobMatch(tb testing.TB, s *Store, pattern string, want ...string) {
+	tb.Helper()
+
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		tb.Fatalf("invalid glob pattern %q: %s", pattern, err)
+	}
+	if got := s.MatchGlob(pattern); !slices.Equal(got, want) {
+		tb.Fatalf("keys matching %q:\n\tgot:  %q\n\twant: %q", pattern, got, want)
+	}
+}
+
+// RequireZeroValueUsable exercises Add, Rm, Has, Len, and String on a zero
+// value Store, one that was never initialized, and fails on any panic or
+// unexpected result. A nil map happily serves reads but panics on writes,
+// this locks in that the Store initializes its map lazily.
+func RequireZeroValueUsable(tb testing.TB) {
+	tb.Helper()
+
+	check := func(s *Store, wantKeys ...string) {
+		tb.Helper()
+
+		if got := s.Len(); got != len(wantKeys) {
+			tb.Errorf("got Len %d, want %d", got, len(wantKeys))
+		}
+		for _, k := range wantKeys {
+			if !s.Has(k) {
+				tb.Errorf("store is missing key %q", k)
+			}
+		}
+		if got, want := s.String(), fmt.Sprint(wantKeys); got != want {
+			tb.Errorf("got String %q, want %q", got, want)
+		}
+	}
+
+	Protect(tb, func() {
+		var s Store
+		check(&s)
+		if s.Has("key") {
+			tb.Error("zero value store has key \"key\"")
+		}
+
+		s.Rm("key")
+		check(&s)
+
+		if err := s.Add("key"); err != nil {
+			tb.Fatalf("failed to add key: %s", err)
+		}
+		check(&s, "key")
+
+		s.Rm("key")
+		check(&s)
+	})
+}
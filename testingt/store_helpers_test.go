@@ -0,0 +1,488 @@
+package testingt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestTx(t *testing.T) {
+	t.Run("error mid-way leaves store unchanged", func(t *testing.T) {
+		var s testingt.Store
+		s.Add("first")
+
+		errBoom := errors.New("boom")
+		err := testingt.Tx(t, &s, func(tx *testingt.Store) error {
+			tx.Add("second")
+			tx.Rm("first")
+			return errBoom
+		})
+		require.ErrorIs(t, err, errBoom)
+
+		assert.Equal(t, []string{"first"}, s.Keys())
+	})
+
+	t.Run("nil error commits", func(t *testing.T) {
+		var s testingt.Store
+		s.Add("first")
+
+		err := testingt.Tx(t, &s, func(tx *testingt.Store) error {
+			tx.Add("second")
+			tx.Add("third")
+			tx.Rm("first")
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"second", "third"}, s.Keys())
+	})
+}
+
+func TestAddKeysStrict(t *testing.T) {
+	t.Run("clean add", func(t *testing.T) {
+		var (
+			s        testingt.Store
+			keysSeen []string
+		)
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.AddKeysStrict(tb, &s, "first", "second")
+			keysSeen = s.Keys()
+		})
+
+		require.False(t, fake.Failed())
+		assert.Equal(t, []string{"first", "second"}, keysSeen)
+		assert.Zero(t, s.Len(), "keys should be removed in cleanup")
+	})
+
+	t.Run("duplicate in args", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.AddKeysStrict(tb, &s, "first", "second", "first")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`duplicate key "first" in args`}, fake.Errors())
+		assert.Zero(t, s.Len())
+	})
+
+	t.Run("pre-existing key", func(t *testing.T) {
+		var s testingt.Store
+		s.Add("second")
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.AddKeysStrict(tb, &s, "first", "second")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`key "second" already exists in store`}, fake.Errors())
+		assert.Equal(t, []string{"second"}, s.Keys())
+	})
+}
+
+func TestRequireStoreError(t *testing.T) {
+	t.Run("adding beyond capacity", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithCapacity(2))
+		require.NoError(t, s.Add("first"))
+		require.NoError(t, s.Add("second"))
+		require.NoError(t, s.Add("second"), "re-adding an existing key should not count against capacity")
+
+		err := s.Add("third")
+		testingt.RequireStoreError(t, err, testingt.ErrCapacityExceeded)
+		assert.Equal(t, []string{"first", "second"}, s.Keys())
+	})
+
+	t.Run("mismatched error", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStoreError(tb, nil, testingt.ErrCapacityExceeded)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"got error <nil>, want store capacity exceeded"}, fake.Errors())
+	})
+}
+
+func TestRequireSubset(t *testing.T) {
+	newStore := func(keys ...string) *testingt.Store {
+		var s testingt.Store
+		for _, k := range keys {
+			require.NoError(t, s.Add(k))
+		}
+		return &s
+	}
+
+	t.Run("proper subset", func(t *testing.T) {
+		sub, super := newStore("a", "b"), newStore("a", "b", "c")
+
+		assert.True(t, sub.IsSubsetOf(super))
+		assert.False(t, super.IsSubsetOf(sub))
+		testingt.RequireSubset(t, sub, super)
+	})
+
+	t.Run("equal sets", func(t *testing.T) {
+		sub, super := newStore("a", "b"), newStore("a", "b")
+
+		assert.True(t, sub.IsSubsetOf(super))
+		testingt.RequireSubset(t, sub, super)
+	})
+
+	t.Run("not a subset", func(t *testing.T) {
+		sub, super := newStore("a", "b", "d", "e"), newStore("a", "c")
+		assert.False(t, sub.IsSubsetOf(super))
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireSubset(tb, sub, super)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`keys missing from superset: ["b" "d" "e"]`}, fake.Errors())
+	})
+}
+
+func TestRequireBoundedMemory(t *testing.T) {
+	var s testingt.Store
+	require.NoError(t, s.Add("keeper"))
+
+	testingt.RequireBoundedMemory(t, &s)
+
+	assert.Equal(t, []string{"keeper"}, s.Keys())
+}
+
+func TestRequireAllKeys(t *testing.T) {
+	isLower := func(k string) bool { return k == strings.ToLower(k) }
+
+	var s testingt.Store
+	require.NoError(t, s.Add("first"))
+	require.NoError(t, s.Add("second"))
+
+	t.Run("all keys valid", func(t *testing.T) {
+		testingt.RequireAllKeys(t, &s, isLower, "keys must be lowercase")
+	})
+
+	t.Run("violating keys", func(t *testing.T) {
+		require.NoError(t, s.Add("Third"))
+		require.NoError(t, s.Add("FOURTH"))
+		t.Cleanup(func() {
+			s.Rm("Third")
+			s.Rm("FOURTH")
+		})
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireAllKeys(tb, &s, isLower, "keys must be lowercase")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`keys must be lowercase: offending keys ["FOURTH" "Third"]`}, fake.Errors())
+	})
+}
+
+func TestDrainInto(t *testing.T) {
+	t.Run("until channel closes", func(t *testing.T) {
+		ch := make(chan string)
+		go func() {
+			defer close(ch)
+			for _, k := range []string{"first", "second", "third", "second"} {
+				ch <- k
+			}
+		}()
+
+		var s testingt.Store
+		n := testingt.DrainInto(t, &s, ch, time.Second)
+
+		assert.Equal(t, 4, n)
+		assert.Equal(t, []string{"first", "second", "third"}, s.Keys())
+	})
+
+	t.Run("until timeout", func(t *testing.T) {
+		ch := make(chan string, 2)
+		ch <- "first"
+		ch <- "second"
+
+		var (
+			s testingt.Store
+			n int
+		)
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			n = testingt.DrainInto(tb, &s, ch, 10*time.Millisecond)
+		})
+
+		require.False(t, fake.Failed())
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{"first", "second"}, s.Keys())
+		assert.Equal(t, []string{"stopped draining after 10ms with 2 key(s) received, channel still open"}, fake.Logs())
+	})
+}
+
+func TestRequireRejectsEmptyKey(t *testing.T) {
+	t.Run("strict mode rejects", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithStrictKeys())
+		require.NoError(t, s.Add("first"))
+
+		testingt.RequireRejectsEmptyKey(t, s)
+		assert.Equal(t, []string{"first"}, s.Keys())
+	})
+
+	t.Run("default mode accepts", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireRejectsEmptyKey(tb, &s)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"adding empty key: got error <nil>, want empty key"}, fake.Errors())
+		assert.True(t, s.Has(""))
+	})
+}
+
+func TestRequireNoCollisions(t *testing.T) {
+	testingt.RequireNoCollisions(t, 100_000)
+}
+
+func TestRequireStoreEqualsMap(t *testing.T) {
+	var s testingt.Store
+	require.NoError(t, s.Add("first"))
+	require.NoError(t, s.Add("second"))
+
+	t.Run("equal", func(t *testing.T) {
+		testingt.RequireStoreEqualsMap(t, &s, map[string]bool{
+			"first":  true,
+			"second": true,
+			"third":  false,
+		})
+	})
+
+	t.Run("differing", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireStoreEqualsMap(tb, &s, map[string]bool{
+				"first": true,
+				"third": true,
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"store does not match map:\n\tgot:  [\"first\" \"second\"]\n\twant: [\"first\" \"third\"]"}, fake.Errors())
+	})
+}
+
+func TestRequireNormalized(t *testing.T) {
+	t.Run("trim normalizer", func(t *testing.T) {
+		s := testingt.NewStore(testingt.WithKeyNormalizer(strings.TrimSpace))
+
+		testingt.RequireNormalized(t, s, " a ", "a")
+		assert.True(t, s.Has("\ta\n"), "Has should normalize too")
+	})
+
+	t.Run("no normalizer", func(t *testing.T) {
+		var s testingt.Store
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireNormalized(tb, &s, " a ", "a")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`adding " a " stored [" a "], want "a"`}, fake.Errors())
+		assert.Zero(t, s.Len(), "key should be removed in cleanup")
+	})
+}
+
+func TestRequireRmSafe(t *testing.T) {
+	t.Run("nil backed store", func(t *testing.T) {
+		var s testingt.Store
+
+		testingt.RequireRmSafe(t, &s, "nope")
+	})
+
+	t.Run("populated store", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("first"))
+		require.NoError(t, s.Add("second"))
+
+		testingt.RequireRmSafe(t, &s, "nope")
+		assert.Equal(t, []string{"first", "second"}, s.Keys())
+	})
+
+	t.Run("present key", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("first"))
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireRmSafe(tb, &s, "first")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`key "first" must be absent from the store`}, fake.Errors())
+	})
+}
+
+func TestRequireValidUTF8(t *testing.T) {
+	t.Run("unicode keys", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s,
+			"🔑",
+			"👩‍💻",
+			"cafe\u0301",
+			"n\u0303o\u0308",
+			"plain",
+		)
+
+		testingt.RequireValidUTF8(t, &s)
+	})
+
+	t.Run("invalid bytes", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("ok"))
+		require.NoError(t, s.Add("\xffbad"))
+
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireValidUTF8(tb, &s)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			`key "\xffbad" is not valid UTF-8`,
+			`String output "[ok \xffbad]" is not valid UTF-8`,
+		}, fake.Errors())
+	})
+}
+
+func TestRequireSnapshotImmutable(t *testing.T) {
+	t.Run("snapshot ignores later adds", func(t *testing.T) {
+		var s testingt.Store
+		require.NoError(t, s.Add("first"))
+
+		snap := s.Snapshot()
+		require.NoError(t, s.Add("second"))
+
+		assert.False(t, snap.Has("second"))
+		assert.Equal(t, []string{"first"}, snap.Keys())
+	})
+
+	t.Run("store is restored", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "first", "second")
+
+		testingt.RequireSnapshotImmutable(t, &s)
+
+		assert.Equal(t, []string{"first", "second"}, s.Keys())
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		var s testingt.Store
+
+		testingt.RequireSnapshotImmutable(t, &s)
+
+		assert.Zero(t, s.Len())
+	})
+}
+
+func TestRequireNearlyEqualSets(t *testing.T) {
+	var want, got testingt.Store
+	testingt.AddKeysStrict(t, &want, "first", "second", "third")
+	testingt.AddKeysStrict(t, &got, "second", "third", "fourth")
+
+	t.Run("within tolerance", func(t *testing.T) {
+		testingt.RequireNearlyEqualSets(t, &want, &got, 2)
+	})
+
+	t.Run("equal sets", func(t *testing.T) {
+		testingt.RequireNearlyEqualSets(t, &want, &want, 0)
+	})
+
+	t.Run("over tolerance", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireNearlyEqualSets(tb, &want, &got, 1)
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			"sets differ by 2 key(s), want at most 1:\n\tmissing: [\"first\"]\n\textra:   [\"fourth\"]",
+		}, fake.Errors())
+	})
+}
+
+func TestRequireStableMemoryOnDup(t *testing.T) {
+	t.Run("empty store", func(t *testing.T) {
+		var s testingt.Store
+
+		testingt.RequireStableMemoryOnDup(t, &s, "dup", 1000)
+
+		assert.Equal(t, []string{"dup"}, s.Keys())
+	})
+
+	t.Run("key already present", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "dup", "other")
+
+		testingt.RequireStableMemoryOnDup(t, &s, "dup", 1000)
+
+		assert.Equal(t, 2, s.Len())
+	})
+}
+
+func TestRequireImplements(t *testing.T) {
+	t.Run("Store", func(t *testing.T) {
+		testingt.RequireImplements(t)
+	})
+
+	t.Run("missing interfaces stop the test", func(t *testing.T) {
+		var continued bool
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireImplementsOf(tb, "struct{}", struct{}{})
+			continued = true
+		})
+
+		require.True(t, fake.Failed())
+		assert.False(t, continued, "test should stop after the failed check")
+		assert.Equal(t, []string{
+			"struct{} does not implement fmt.Stringer",
+			"struct{} does not implement json.Marshaler",
+			"struct{} does not implement KeyStore",
+		}, fake.Errors())
+	})
+}
+
+func TestRequireGlobMatch(t *testing.T) {
+	var s testingt.Store
+	testingt.AddKeysStrict(t, &s,
+		"user:alice",
+		"user:bob",
+		"user:admin:carol",
+		"group:admins",
+		"group:users",
+	)
+
+	t.Run("wildcards", func(t *testing.T) {
+		testingt.RequireGlobMatch(t, &s, "user:*", "user:admin:carol", "user:alice", "user:bob")
+		testingt.RequireGlobMatch(t, &s, "user:?ob", "user:bob")
+		testingt.RequireGlobMatch(t, &s, "group:[a-m]*", "group:admins")
+		testingt.RequireGlobMatch(t, &s, "*:*s", "group:admins", "group:users")
+		testingt.RequireGlobMatch(t, &s, "team:*")
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireGlobMatch(tb, &s, "group:*", "group:admins")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{
+			"keys matching \"group:*\":\n\tgot:  [\"group:admins\" \"group:users\"]\n\twant: [\"group:admins\"]",
+		}, fake.Errors())
+	})
+
+	t.Run("malformed pattern", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireGlobMatch(tb, &s, "[user")
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{`invalid glob pattern "[user": syntax error in pattern`}, fake.Errors())
+	})
+}
+
+func TestRequireZeroValueUsable(t *testing.T) {
+	testingt.RequireZeroValueUsable(t)
+}
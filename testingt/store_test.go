@@ -0,0 +1,140 @@
+package testingt_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestStore_Format(t *testing.T) {
+	t.Run("sorted keys joined by sep", func(t *testing.T) {
+		var s testingt.Store
+		s.Add("c")
+		s.Add("a")
+		s.Add("b")
+
+		assert.Equal(t, "a, b, c", s.Format(", "))
+		assert.Equal(t, "a|b|c", s.Format("|"))
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		var s testingt.Store
+
+		assert.Equal(t, "", s.Format(", "))
+	})
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	var s testingt.Store
+	s.Add("first")
+	s.Add("second")
+
+	snap := s.Snapshot()
+
+	s.Rm("first")
+	s.Add("third")
+	assert.Equal(t, []string{"first", "second"}, snap.Keys(), "snapshot should not see mutations")
+
+	s.Restore(snap)
+	assert.Equal(t, snap.Keys(), s.Keys())
+	assert.Equal(t, snap.Len(), s.Len())
+
+	s.Add("fourth")
+	assert.False(t, snap.Has("fourth"), "restored store should not alias the snapshot")
+}
+
+func TestStore_Compact(t *testing.T) {
+	var s testingt.Store
+	s.Compact()
+	assert.Zero(t, s.Len(), "compacting a zero value store is a no-op")
+
+	for _, k := range []string{"a", "b", "c"} {
+		assert.NoError(t, s.Add(k))
+	}
+	s.Rm("b")
+	s.Compact()
+
+	assert.Equal(t, []string{"a", "c"}, s.Keys())
+}
+
+func TestStore_WithRateLimit(t *testing.T) {
+	const (
+		rps  = 10
+		adds = 30
+	)
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := testingt.NewFakeClock(start)
+	s := testingt.NewStore(testingt.WithRateLimit(rps), testingt.WithClock(clock))
+
+	for i := range adds {
+		require.NoError(t, s.Add(strconv.Itoa(i)))
+	}
+
+	// the first rps adds are covered by the burst, each one after
+	// that has to wait a full 1/rps for its token
+	want := time.Duration(adds-rps) * time.Second / rps
+	assert.GreaterOrEqual(t, clock.Now().Sub(start), want)
+	assert.Equal(t, adds, s.Len())
+
+	before := clock.Now()
+	s.Rm("0")
+	assert.Equal(t, time.Second/rps, clock.Now().Sub(before), "Rm should be throttled too")
+}
+
+func TestStore_DOT(t *testing.T) {
+	t.Run("a node per key", func(t *testing.T) {
+		var s testingt.Store
+		for _, k := range []string{"first", "second", `with "quotes"`} {
+			require.NoError(t, s.Add(k))
+		}
+
+		dot := s.DOT()
+
+		assert.True(t, strings.HasPrefix(dot, "digraph store {\n"))
+		for _, k := range s.Keys() {
+			q := strconv.Quote(k)
+			assert.Contains(t, dot, "\t"+q+";\n")
+			assert.Contains(t, dot, "\tstore -> "+q+";\n")
+		}
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		var s testingt.Store
+
+		assert.Equal(t, "digraph store {\n\tstore [shape=box];\n}\n", s.DOT())
+	})
+}
+
+func TestStore_MarshalJSON(t *testing.T) {
+	t.Run("sorted keys", func(t *testing.T) {
+		var s testingt.Store
+		testingt.AddKeysStrict(t, &s, "second", "first")
+
+		b, err := json.Marshal(&s)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["first","second"]`, string(b))
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		b, err := json.Marshal(new(testingt.Store))
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(b))
+	})
+}
+
+func TestStore_MatchGlob(t *testing.T) {
+	var s testingt.Store
+	testingt.AddKeysStrict(t, &s, "user:2", "user:1", "group:1")
+
+	assert.Equal(t, []string{"user:1", "user:2"}, s.MatchGlob("user:*"))
+	assert.Empty(t, s.MatchGlob("nope:*"))
+	assert.Empty(t, s.MatchGlob("[user"), "malformed pattern should match nothing")
+}
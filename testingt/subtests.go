@@ -0,0 +1,76 @@
+package testingt
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// Runner starts subtests. *testing.T satisfies it, write subtest generators
+// against Runner to be able to hand them a recording wrapper in tests.
+type Runner interface {
+	Run(name string, fn func(t *testing.T)) bool
+}
+
+type subtestRecorder struct {
+	t     *testing.T
+	names []string
+}
+
+func (r *subtestRecorder) Run(name string, fn func(t *testing.T)) bool {
+	r.names = append(r.names, name)
+	return r.t.Run(name, fn)
+}
+
+// RequireSubtestNames calls run with a Runner that records the name of every
+// subtest started through it, then fatals unless the names match want, in
+// order. Names are recorded as given to Run, before the testing package
+// rewrites them (e.g. spaces to underscores). run takes a Runner rather
+// than a *testing.T, since *testing.T is a concrete type and there's no way
+// to intercept the Run calls made on it.
+func RequireSubtestNames(t *testing.T, run func(r Runner), want []string) {
+	t.Helper()
+
+	rec := &subtestRecorder{t: t}
+	run(rec)
+	if !slices.Equal(rec.names, want) {
+		t.Fatalf("unexpected subtest names:\n\tgot:  %q\n\twant: %q", rec.names, want)
+	}
+}
+
+// RunParallelTable runs a parallel subtest per case, named by its key, and
+// registers a cleanup on t that fails unless every case ran to completion.
+// A case completes when run returns or skips the case. One cut short by
+// FailNow, or a require built on it, is reported. The cases only start
+// once the calling test's func returns, so wrap the call in a t.Run to
+// wait on the table.
+func RunParallelTable[C any](t *testing.T, cases map[string]C, run func(t *testing.T, c C)) {
+	t.Helper()
+
+	var completed atomic.Int32
+	t.Cleanup(func() {
+		if n := int(completed.Load()); n != len(cases) {
+			t.Errorf("only %d of %d parallel cases completed", n, len(cases))
+		}
+	})
+
+	for _, name := range slices.Sorted(maps.Keys(cases)) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// FailNow and SkipNow both run defers on their way out of the
+			// case, so a skip is told apart from a return cut short by
+			// asking t
+			var returned bool
+			defer func() {
+				if returned || t.Skipped() {
+					completed.Add(1)
+				}
+			}()
+
+			run(t, cases[name])
+			returned = true
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package testingt_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireSubtestNames(t *testing.T) {
+	generate := func(r testingt.Runner) {
+		for i := range 3 {
+			r.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+				t.Log("running generated case")
+			})
+		}
+	}
+
+	testingt.RequireSubtestNames(t, generate, []string{"case 1", "case 2", "case 3"})
+}
+
+func TestRequireSubtestNames_Mismatch(t *testing.T) {
+	// RequireSubtestNames fails the *testing.T it records subtests on, so
+	// the mismatch happens in a child test process and the parent asserts
+	// on its output.
+	if os.Getenv("TESTINGT_SUBTEST_MISMATCH") == "1" {
+		testingt.RequireSubtestNames(t, func(r testingt.Runner) {
+			r.Run("first", func(t *testing.T) {})
+			r.Run("third", func(t *testing.T) {})
+		}, []string{"first", "second"})
+		fmt.Println("continued after the mismatch")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRequireSubtestNames_Mismatch$", "-test.v")
+	cmd.Env = append(os.Environ(), "TESTINGT_SUBTEST_MISMATCH=1")
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr, "child test should fail:\n%s", out)
+	assert.Contains(t, string(out), "unexpected subtest names")
+	assert.Contains(t, string(out), `got:  ["first" "third"]`)
+	assert.Contains(t, string(out), `want: ["first" "second"]`)
+	assert.NotContains(t, string(out), "continued after the mismatch")
+}
+
+func TestRunParallelTable(t *testing.T) {
+	const sleep = 100 * time.Millisecond
+
+	cases := map[string]time.Duration{
+		"first":  sleep,
+		"second": sleep,
+		"third":  sleep,
+		"fourth": sleep,
+	}
+
+	var ran atomic.Int32
+	start := time.Now()
+	t.Run("table", func(t *testing.T) {
+		testingt.RunParallelTable(t, cases, func(t *testing.T, d time.Duration) {
+			time.Sleep(d)
+			ran.Add(1)
+		})
+	})
+	elapsed := time.Since(start)
+	t.Logf("ran %d cases in %s", ran.Load(), elapsed)
+
+	assert.Equal(t, int32(len(cases)), ran.Load())
+
+	// -parallel defaults to GOMAXPROCS, single core machines can't overlap
+	if p := flag.Lookup("test.parallel").Value.(flag.Getter).Get().(int); p < 2 {
+		t.Skipf("need -parallel of at least 2 to observe overlap, got %d", p)
+	}
+	assert.Less(t, elapsed, time.Duration(len(cases))*sleep, "cases should have overlapped")
+}
+
+func TestRunParallelTable_Aborted(t *testing.T) {
+	// a case cut short only fails the table in its parent's cleanup, which
+	// fails the whole test. So the table runs in a child test process, with
+	// the middle case aborting as the env var says, and the parent asserts
+	// on its output.
+	if abort := os.Getenv("TESTINGT_ABORTED_TABLE"); abort != "" {
+		testingt.RunParallelTable(t, map[string]bool{"first": false, "second": true, "third": false}, func(t *testing.T, aborts bool) {
+			if !aborts {
+				return
+			}
+			if abort == "skip" {
+				t.SkipNow()
+			}
+			t.FailNow()
+		})
+		return
+	}
+
+	runChild := func(abort string) (string, error) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRunParallelTable_Aborted$", "-test.v")
+		cmd.Env = append(os.Environ(), "TESTINGT_ABORTED_TABLE="+abort)
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	t.Run("skipped case passes", func(t *testing.T) {
+		out, err := runChild("skip")
+
+		require.NoError(t, err, "child test should pass:\n%s", out)
+		assert.NotContains(t, out, "parallel cases completed")
+	})
+
+	t.Run("FailNow'd case fails", func(t *testing.T) {
+		out, err := runChild("failnow")
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr, "child test should fail:\n%s", out)
+		assert.Contains(t, out, "only 2 of 3 parallel cases completed")
+	})
+}
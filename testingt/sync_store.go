@@ -0,0 +1,61 @@
+package testingt
+
+import "sync"
+
+// SyncStore is a Store that is safe for concurrent use. The zero value is
+// ready to use.
+type SyncStore struct {
+	mu    sync.RWMutex
+	store Store
+}
+
+var _ KeyStore = (*SyncStore)(nil)
+
+// Add adds the key to the store.
+func (s *SyncStore) Add(k string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.Add(k)
+}
+
+// Rm removes the key from the store.
+func (s *SyncStore) Rm(k string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store.Rm(k)
+}
+
+// Has reports whether the key is in the store.
+func (s *SyncStore) Has(k string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.Has(k)
+}
+
+// Len returns the number of keys in the store.
+func (s *SyncStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.Len()
+}
+
+// Keys returns the keys in sorted order.
+func (s *SyncStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.Keys()
+}
+
+// Snapshot returns an immutable copy of the store's current state, taken
+// under a single lock so it's always internally consistent.
+func (s *SyncStore) Snapshot() StoreSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.Snapshot()
+}
+
+func (s *SyncStore) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.String()
+}
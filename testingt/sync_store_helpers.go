@@ -0,0 +1,164 @@
+package testingt
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// RequireSnapshotConsistent runs mutators and snapshotters against s
+// concurrently and fails if any snapshot is internally inconsistent, i.e.
+// its Len doesn't match the number of Keys it holds. Run it with -race to
+// catch unsynchronized access along the way.
+func RequireSnapshotConsistent(tb testing.TB, s *SyncStore) {
+	tb.Helper()
+
+	const (
+		workers = 4
+		iters   = 500
+	)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range iters {
+				k := "snap-" + strconv.Itoa(w) + "-" + strconv.Itoa(i%10)
+				if i%3 == 2 {
+					s.Rm(k)
+					continue
+				}
+				if err := s.Add(k); err != nil {
+					tb.Errorf("failed to add key %q: %s", k, err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range iters {
+				snap := s.Snapshot()
+				if n, keys := snap.Len(), snap.Keys(); n != len(keys) {
+					tb.Errorf("inconsistent snapshot: Len()=%d but holds %d keys", n, len(keys))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// RaceCheck runs a small concurrent workload of writers and readers against
+// s. It is meant to be run with -race: a KeyStore that isn't safe for
+// concurrent use, like a bare Store, trips the race detector and fails the
+// test binary, while a SyncStore passes. Without -race, only the runtime's
+// best effort concurrent map access check stands between it and passing.
+// Each writer removes the keys it adds, so a passing run leaves s with the
+// keys it started with.
+func RaceCheck(tb testing.TB, s KeyStore) {
+	tb.Helper()
+
+	const (
+		workers = 4
+		iters   = 100
+	)
+
+	before := s.Len()
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range iters {
+				k := "race-" + strconv.Itoa(w) + "-" + strconv.Itoa(i)
+				if err := s.Add(k); err != nil {
+					tb.Errorf("failed to add key %q: %s", k, err)
+					return
+				}
+				s.Rm(k)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range iters {
+				s.Has("race-" + strconv.Itoa(w) + "-" + strconv.Itoa(i))
+				s.Len()
+				s.Keys()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if after := s.Len(); after != before {
+		tb.Errorf("store has %d keys after the workload, want %d", after, before)
+	}
+}
+
+// RequireVisibleAfter adds key to s in one goroutine and, once that
+// goroutine signals a barrier, fatals unless a second goroutine sees
+// Has(key). Run it with -race, which checks the happens-before edge the
+// barrier and the store's lock are meant to provide. key is left in s.
+func RequireVisibleAfter(tb testing.TB, s *SyncStore, key string) {
+	tb.Helper()
+
+	var (
+		barrier = make(chan struct{})
+		addErr  = make(chan error, 1)
+		seen    = make(chan bool, 1)
+	)
+	go func() {
+		addErr <- s.Add(key)
+		close(barrier)
+	}()
+	go func() {
+		<-barrier
+		seen <- s.Has(key)
+	}()
+
+	if err := <-addErr; err != nil {
+		tb.Fatalf("failed to add key %q: %s", key, err)
+	}
+	if !<-seen {
+		tb.Fatalf("key %q added before the barrier is not visible after it", key)
+	}
+}
+
+// RequireConcurrentWritersConverge runs write once for each writer id
+// concurrently against a shared SyncStore, then fatals unless the shared
+// store ends up with exactly the union of the keys each writer leaves
+// behind when run on its own, against a fresh store. Writers should only
+// touch keys of their own, one removing another's keys has no single
+// intended result. Run it with -race to check the writes along the way.
+func RequireConcurrentWritersConverge(tb testing.TB, write func(s *SyncStore, id int), writers int) {
+	tb.Helper()
+
+	var want Store
+	for id := range writers {
+		var alone SyncStore
+		write(&alone, id)
+		for _, k := range alone.Keys() {
+			if err := want.Add(k); err != nil {
+				tb.Fatalf("failed to add key %q: %s", k, err)
+			}
+		}
+	}
+
+	var (
+		shared SyncStore
+		wg     sync.WaitGroup
+	)
+	for id := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			write(&shared, id)
+		}()
+	}
+	wg.Wait()
+
+	if got := shared.Keys(); !slices.Equal(got, want.Keys()) {
+		tb.Fatalf("concurrent writers did not converge on the union of their writes:\n\tgot:  %q\n\twant: %q", got, want.Keys())
+	}
+}
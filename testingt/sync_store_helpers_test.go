@@ -0,0 +1,103 @@
+package testingt_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireSnapshotConsistent(t *testing.T) {
+	// run with -race to make the most of this one
+	var s testingt.SyncStore
+	testingt.RequireSnapshotConsistent(t, &s)
+}
+
+func TestRaceCheck(t *testing.T) {
+	t.Run("SyncStore passes", func(t *testing.T) {
+		var s testingt.SyncStore
+		testingt.RaceCheck(t, &s)
+	})
+
+	t.Run("Store is flagged", func(t *testing.T) {
+		// a bare Store isn't safe for concurrent use, under -race RaceCheck
+		// against one fails the whole test binary with a race report. So
+		// it runs in a child test process, built with -race just like this
+		// one, and the parent asserts on its output.
+		if os.Getenv("TESTINGT_RACY_STORE") == "1" {
+			var s testingt.Store
+			testingt.RaceCheck(t, &s)
+			return
+		}
+		if !raceEnabled() {
+			t.Skip("the race detector is what flags a bare Store, run with -race")
+		}
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRaceCheck$/^Store_is_flagged$", "-test.v")
+		cmd.Env = append(os.Environ(), "TESTINGT_RACY_STORE=1")
+		out, err := cmd.CombinedOutput()
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr, "child test should fail:\n%s", out)
+		assert.Contains(t, string(out), "DATA RACE")
+	})
+}
+
+// raceEnabled reports whether the test binary was built with -race.
+func raceEnabled() bool {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return false
+	}
+	for _, s := range info.Settings {
+		if s.Key == "-race" {
+			return s.Value == "true"
+		}
+	}
+	return false
+}
+
+func TestRequireVisibleAfter(t *testing.T) {
+	// run with -race to make the most of this one
+	var s testingt.SyncStore
+	testingt.RequireVisibleAfter(t, &s, "first")
+
+	if !s.Has("first") {
+		t.Fatal("key should be left in the store")
+	}
+}
+
+func TestRequireConcurrentWritersConverge(t *testing.T) {
+	t.Run("writers touching their own keys", func(t *testing.T) {
+		// run with -race to make the most of this one
+		testingt.RequireConcurrentWritersConverge(t, func(s *testingt.SyncStore, id int) {
+			for i := range 20 {
+				k := "writer-" + strconv.Itoa(id) + "-" + strconv.Itoa(i)
+				_ = s.Add(k)
+				if i%2 == 1 {
+					s.Rm(k)
+				}
+			}
+		}, 8)
+	})
+
+	t.Run("writes depending on outside state", func(t *testing.T) {
+		var calls atomic.Int32
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireConcurrentWritersConverge(tb, func(s *testingt.SyncStore, id int) {
+				_ = s.Add("call-" + strconv.Itoa(int(calls.Add(1))))
+			}, 2)
+		})
+
+		if !fake.Failed() {
+			t.Fatal("writers depending on how often they've been called should not converge")
+		}
+	})
+}
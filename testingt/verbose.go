@@ -0,0 +1,44 @@
+package testingt
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// quiet silences the informational logs of the helpers in this package when
+// set. It's inverted so the zero value is verbose.
+var quiet atomic.Bool
+
+// SetVerbose toggles the informational logging done by the helpers in this
+// package, e.g. Rand's seed or FlakeCheck's flake rate, and whether a
+// LogToFile TB forwards its logs to the test as well as the file. Failures
+// are always reported. Helpers log by default.
+//
+// The toggle is package wide, don't flip it from parallel tests.
+func SetVerbose(v bool) {
+	quiet.Store(!v)
+}
+
+// logf logs to tb unless the package has been made quiet.
+func logf(tb testing.TB, format string, args ...any) {
+	tb.Helper()
+
+	if !quiet.Load() {
+		tb.Logf(format, args...)
+	}
+}
+
+// RequireNoLogsWhenQuiet runs fn against a FakeTB with verbosity turned off
+// and fails if any log reached the TB. Verbosity is restored afterwards.
+func RequireNoLogsWhenQuiet(tb testing.TB, fn func(tb testing.TB)) {
+	tb.Helper()
+
+	wasQuiet := quiet.Load()
+	SetVerbose(false)
+	defer quiet.Store(wasQuiet)
+
+	if logs := RunFakeTB(tb, fn).Logs(); len(logs) > 0 {
+		tb.Errorf("expected no logs while quiet, got %d:\n\t%s", len(logs), strings.Join(logs, "\n\t"))
+	}
+}
@@ -0,0 +1,47 @@
+package testingt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsteenb2/demo/testingt"
+)
+
+func TestRequireNoLogsWhenQuiet(t *testing.T) {
+	t.Run("quiet helpers don't log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "quiet.log")
+
+		testingt.RequireNoLogsWhenQuiet(t, func(tb testing.TB) {
+			testingt.Rand(tb)
+			testingt.FlakeCheck(tb, 2, func(tb testing.TB) bool { return true })
+			testingt.LogToFile(tb, path).Log("only in the file")
+		})
+
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "only in the file\n", string(b))
+	})
+
+	t.Run("direct logs are caught", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.RequireNoLogsWhenQuiet(tb, func(tb testing.TB) {
+				tb.Log("chatty")
+			})
+		})
+
+		require.True(t, fake.Failed())
+		assert.Equal(t, []string{"expected no logs while quiet, got 1:\n\tchatty"}, fake.Errors())
+	})
+
+	t.Run("verbose helpers log", func(t *testing.T) {
+		fake := testingt.RunFakeTB(t, func(tb testing.TB) {
+			testingt.Rand(tb)
+		})
+
+		assert.Len(t, fake.Logs(), 1, "verbosity should be restored after RequireNoLogsWhenQuiet")
+	})
+}